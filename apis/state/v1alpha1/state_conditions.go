@@ -0,0 +1,178 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Condition kinds of the collection of a state entry. Synced is used by the
+// reconciler for the reconcile status, the initial sync of the subscription is
+// reported as DataSynced.
+const (
+	// ConditionKindSubscribed indicates whether the subscription of the state entry is running
+	ConditionKindSubscribed nddv1.ConditionKind = "Subscribed"
+	// ConditionKindDataSynced indicates whether the initial sync of the subscription completed
+	ConditionKindDataSynced nddv1.ConditionKind = "DataSynced"
+	// ConditionKindDegraded indicates whether the subscription reported an error
+	ConditionKindDegraded nddv1.ConditionKind = "Degraded"
+	// ConditionKindTargetReachable indicates whether the worker is connected to the target
+	ConditionKindTargetReachable nddv1.ConditionKind = "TargetReachable"
+)
+
+// Reasons of the conditions of the collection of a state entry.
+const (
+	ConditionReasonSubscribed        nddv1.ConditionReason = "Subscribed"
+	ConditionReasonNotSubscribed     nddv1.ConditionReason = "NotSubscribed"
+	ConditionReasonSyncReceived      nddv1.ConditionReason = "SyncResponseReceived"
+	ConditionReasonSyncPending       nddv1.ConditionReason = "SyncResponsePending"
+	ConditionReasonSubscriptionError nddv1.ConditionReason = "SubscriptionError"
+	ConditionReasonHealthy           nddv1.ConditionReason = "Healthy"
+	ConditionReasonUnsupported       nddv1.ConditionReason = "Unsupported"
+	ConditionReasonDowngraded        nddv1.ConditionReason = "Downgraded"
+	ConditionReasonReachable         nddv1.ConditionReason = "Reachable"
+	ConditionReasonUnreachable       nddv1.ConditionReason = "Unreachable"
+	ConditionReasonPending           nddv1.ConditionReason = "Pending"
+)
+
+// Subscribed returns a condition that indicates the subscription of the
+// state entry is running.
+func Subscribed() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindSubscribed,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonSubscribed,
+	}
+}
+
+// NotSubscribed returns a condition that indicates the subscription of the
+// state entry is not running.
+func NotSubscribed() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindSubscribed,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonNotSubscribed,
+	}
+}
+
+// SubscriptionRejected returns a condition that indicates the state entry is
+// not subscribed since the target does not support it.
+func SubscriptionRejected(msg string) nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindSubscribed,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonUnsupported,
+		Message:            msg,
+	}
+}
+
+// DataSynced returns a condition that indicates the target completed the
+// initial sync of the subscription of the state entry.
+func DataSynced() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindDataSynced,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonSyncReceived,
+	}
+}
+
+// DataNotSynced returns a condition that indicates the target did not yet
+// complete the initial sync of the subscription of the state entry.
+func DataNotSynced() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindDataSynced,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonSyncPending,
+	}
+}
+
+// Degraded returns a condition that indicates the subscription of the state
+// entry reported an error.
+func Degraded(msg string) nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindDegraded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonSubscriptionError,
+		Message:            msg,
+	}
+}
+
+// Downgraded returns a condition that indicates subscription settings of the
+// state entry are downgraded to what the target supports.
+func Downgraded(msg string) nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindDegraded,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonDowngraded,
+		Message:            msg,
+	}
+}
+
+// NotDegraded returns a condition that indicates the subscription of the
+// state entry is healthy.
+func NotDegraded() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindDegraded,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonHealthy,
+	}
+}
+
+// TargetReachable returns a condition that indicates the worker is connected
+// to the target of the state entry.
+func TargetReachable() nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindTargetReachable,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonReachable,
+	}
+}
+
+// TargetUnreachable returns a condition that indicates the worker cannot
+// connect to the target of the state entry.
+func TargetUnreachable(msg string) nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindTargetReachable,
+		Status:             corev1.ConditionFalse,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonUnreachable,
+		Message:            msg,
+	}
+}
+
+// TargetReachabilityPending returns a condition that indicates the worker did not
+// connect to the target of the state entry yet.
+func TargetReachabilityPending(msg string) nddv1.Condition {
+	return nddv1.Condition{
+		Kind:               ConditionKindTargetReachable,
+		Status:             corev1.ConditionUnknown,
+		LastTransitionTime: metav1.Now(),
+		Reason:             ConditionReasonPending,
+		Message:            msg,
+	}
+}
@@ -36,6 +36,8 @@ type StateSpec struct {
 // A StateStatus represents the observed state of a State.
 type StateStatus struct {
 	nddv1.ResourceStatus `json:",inline"`
+	// LastUpdate is the time the worker received the last update for the state entry
+	LastUpdate *metav1.Time `json:"lastUpdate,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -45,6 +47,10 @@ type StateStatus struct {
 // +kubebuilder:printcolumn:name="TARGET",type="string",JSONPath=".status.conditions[?(@.kind=='TargetFound')].status"
 // +kubebuilder:printcolumn:name="STATUS",type="string",JSONPath=".status.conditions[?(@.kind=='Ready')].status"
 // +kubebuilder:printcolumn:name="SYNC",type="string",JSONPath=".status.conditions[?(@.kind=='Synced')].status"
+// +kubebuilder:printcolumn:name="SUBSCRIBED",type="string",JSONPath=".status.conditions[?(@.kind=='Subscribed')].status"
+// +kubebuilder:printcolumn:name="DATA-SYNCED",type="string",JSONPath=".status.conditions[?(@.kind=='DataSynced')].status"
+// +kubebuilder:printcolumn:name="DEGRADED",type="string",JSONPath=".status.conditions[?(@.kind=='Degraded')].status"
+// +kubebuilder:printcolumn:name="LAST-UPDATE",type="date",JSONPath=".status.lastUpdate"
 // +kubebuilder:printcolumn:name="AGE",type="date",JSONPath=".metadata.creationTimestamp"
 // +kubebuilder:resource:categories={ndd,nddp}
 type State struct {
@@ -38,7 +38,7 @@ var m = &model.Model{
 	StructRootType:  reflect.TypeOf((*ygotnddpstate.YnddState_StateEntry)(nil)),
 	SchemaTreeRoot:  ygotnddpstate.SchemaTree["NddpState_StateEntry"],
 	JsonUnmarshaler: ygotnddpstate.Unmarshal,
-	//EnumData:        ygotnddpstate.ΛEnum,
+	EnumData:        ygotnddpstate.ΛEnum,
 }
 
 func (r *State) SetupWebhookWithManager(mgr ctrl.Manager) error {
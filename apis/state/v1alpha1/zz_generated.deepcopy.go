@@ -105,6 +105,10 @@ func (in *StateSpec) DeepCopy() *StateSpec {
 func (in *StateStatus) DeepCopyInto(out *StateStatus) {
 	*out = *in
 	in.ResourceStatus.DeepCopyInto(&out.ResourceStatus)
+	if in.LastUpdate != nil {
+		in, out := &in.LastUpdate, &out.LastUpdate
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StateStatus.
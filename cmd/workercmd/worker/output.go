@@ -0,0 +1,128 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package worker
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/state/internal/collector"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// keys of the mq tls secret
+	secretKeyCA   = "ca.crt"
+	secretKeyCert = corev1.TLSCertKey
+	secretKeyKey  = corev1.TLSPrivateKeyKey
+	// keys of the mq credentials secret
+	secretKeyUsername = corev1.BasicAuthUsernameKey
+	secretKeyPassword = corev1.BasicAuthPasswordKey
+	secretKeyNKey     = "nkey"
+	secretKeyCreds    = "creds"
+
+	// errors
+	errGetSecret      = "cannot get secret"
+	errInvalidCA      = "cannot parse ca certificate"
+	errInvalidKeyPair = "cannot parse client certificate and key"
+)
+
+// newOutputs creates the outputs selected through the outputs flag, every output
+// writes the messages from its own queue such that a slow output does not hold up the others
+func newOutputs(ctx context.Context, cl client.Client, log logging.Logger) ([]collector.Output, error) {
+	outs := make([]collector.Output, 0, len(outputs))
+	for _, kind := range outputs {
+		switch kind {
+		case collector.OutputKindNATS:
+			cfg, err := newNATSConfig(ctx, cl)
+			if err != nil {
+				return nil, err
+			}
+			o, err := collector.NewNATSOutput(ctx, cfg, log)
+			if err != nil {
+				return nil, err
+			}
+			outs = append(outs, collector.NewAsyncOutput(kind, o, outputQueueSize, log))
+		case collector.OutputKindStdout:
+			outs = append(outs, collector.NewAsyncOutput(kind, collector.NewStdoutOutput(), outputQueueSize, log))
+		case collector.OutputKindFile:
+			o, err := collector.NewFileOutput(outputFile)
+			if err != nil {
+				return nil, err
+			}
+			outs = append(outs, collector.NewAsyncOutput(kind, o, outputQueueSize, log))
+		case collector.OutputKindMemory:
+			outs = append(outs, collector.NewAsyncOutput(kind, collector.NewMemoryOutput(0), outputQueueSize, log))
+		default:
+			return nil, fmt.Errorf("unknown output kind: %s", kind)
+		}
+	}
+	return outs, nil
+}
+
+// newNATSConfig builds the nats config from the mq flags, the tls and credentials
+// are loaded from the secrets in the pod namespace
+func newNATSConfig(ctx context.Context, cl client.Client) (*collector.NATSConfig, error) {
+	cfg := &collector.NATSConfig{
+		Address: mqAddress,
+	}
+	if mqTLSSecret != "" {
+		secret, err := getSecret(ctx, cl, mqTLSSecret)
+		if err != nil {
+			return nil, err
+		}
+		cfg.TLS = &tls.Config{}
+		if ca, ok := secret.Data[secretKeyCA]; ok {
+			cfg.TLS.RootCAs = x509.NewCertPool()
+			if !cfg.TLS.RootCAs.AppendCertsFromPEM(ca) {
+				return nil, errors.New(errInvalidCA)
+			}
+		}
+		if cert, ok := secret.Data[secretKeyCert]; ok {
+			kp, err := tls.X509KeyPair(cert, secret.Data[secretKeyKey])
+			if err != nil {
+				return nil, errors.Wrap(err, errInvalidKeyPair)
+			}
+			cfg.TLS.Certificates = []tls.Certificate{kp}
+		}
+	}
+	if mqCredentialsSecret != "" {
+		secret, err := getSecret(ctx, cl, mqCredentialsSecret)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Username = string(secret.Data[secretKeyUsername])
+		cfg.Password = string(secret.Data[secretKeyPassword])
+		cfg.NKeySeed = secret.Data[secretKeyNKey]
+		cfg.Credentials = secret.Data[secretKeyCreds]
+	}
+	return cfg, nil
+}
+
+func getSecret(ctx context.Context, cl client.Client, name string) (*corev1.Secret, error) {
+	secret := &corev1.Secret{}
+	if err := cl.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, secret); err != nil {
+		return nil, errors.Wrapf(err, "%s %s/%s", errGetSecret, namespace, name)
+	}
+	return secret, nil
+}
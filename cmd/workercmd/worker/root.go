@@ -26,6 +26,7 @@ import (
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 
 	//srlv1alpha1 "github.com/yndd/state/apis/srl/v1alpha1"
+	statev1alpha1 "github.com/yndd/state/apis/state/v1alpha1"
 	targetv1 "github.com/yndd/target/apis/target/v1"
 	//+kubebuilder:scaffold:imports
 )
@@ -60,5 +61,6 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 	//utilruntime.Must(srlv1alpha1.AddToScheme(scheme))
 	utilruntime.Must(targetv1.AddToScheme(scheme))
+	utilruntime.Must(statev1alpha1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
@@ -41,8 +41,11 @@ import (
 	"github.com/yndd/ndd-runtime/pkg/shared"
 	"github.com/yndd/state/internal/collector"
 	itarget "github.com/yndd/state/internal/controllers/target"
+	"github.com/yndd/state/internal/lastvalue"
+	"github.com/yndd/state/internal/promexporter"
 	"github.com/yndd/state/internal/stategnmihandler"
 	"github.com/yndd/state/internal/statetargetcontroller"
+	"github.com/yndd/state/pkg/entrystatus"
 	"github.com/yndd/state/pkg/ygotnddpstate"
 	"github.com/yndd/target/pkg/targetcontroller"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -68,6 +71,21 @@ var (
 	serviceDiscovery          string
 	serviceDiscoveryNamespace string // todo initialization
 	mqAddress                 string
+	outputs                   []string
+	outputFile                string
+	mqTLSSecret               string
+	mqCredentialsSecret       string
+	queueSize                 int
+	outputQueueSize           int
+	overflowPolicy            string
+	messageFormat             string
+	prometheusAddr            string
+	retryInitialInterval      time.Duration
+	retryMaxInterval          time.Duration
+	retryMultiplier           float64
+	retryJitter               float64
+	reconcileDelay            time.Duration
+	grpcCertDir               string
 )
 
 // startCmd represents the start command for the network device driver
@@ -116,11 +134,55 @@ var startCmd = &cobra.Command{
 		// initialize the cache
 		c := cache.New()
 
+		// initialize the outputs the collected state is published to
+		outs, err := newOutputs(cmd.Context(), cl, logger)
+		if err != nil {
+			return errors.Wrap(err, "Cannot create outputs")
+		}
+
+		if err := collector.ValidateOverflowPolicy(collector.OverflowPolicy(overflowPolicy)); err != nil {
+			return errors.Wrap(err, "Cannot create collector")
+		}
+		if err := collector.ValidateMessageFormat(collector.MessageFormat(messageFormat)); err != nil {
+			return errors.Wrap(err, "Cannot create collector")
+		}
+
+		// initialize the cache that keeps the last collected values
+		lvc := lastvalue.New(
+			lastvalue.WithLogger(logger),
+		)
+
+		// initialize the prometheus exporter of the collected numeric values
+		var exp promexporter.Exporter
+		if prometheusAddr != "" {
+			exp = promexporter.New(
+				promexporter.WithLogger(logger),
+			)
+			if err := exp.Start(cmd.Context(), prometheusAddr); err != nil {
+				return errors.Wrap(err, "Cannot start prometheus exporter")
+			}
+		}
+
+		backoff := collector.Backoff{
+			Initial:    retryInitialInterval,
+			Max:        retryMaxInterval,
+			Multiplier: retryMultiplier,
+			Jitter:     retryJitter,
+		}
+		if err := backoff.Validate(); err != nil {
+			return errors.Wrap(err, "Cannot create collector")
+		}
+
 		// initialize the colllector
 		col := collector.New(cmd.Context(),
 			collector.WithLogger(logger),
 			collector.WithCache(c),
-			collector.WithMQAddress(""),
+			collector.WithOutputs(outs...),
+			collector.WithQueue(queueSize, collector.OverflowPolicy(overflowPolicy)),
+			collector.WithMessageFormat(collector.MessageFormat(messageFormat)),
+			collector.WithLastValueCache(lvc),
+			collector.WithExporter(exp),
+			collector.WithBackoff(backoff),
 		)
 
 		// create a state target controller for creataing/deleting targets
@@ -136,7 +198,7 @@ var startCmd = &cobra.Command{
 				StructRootType:  reflect.TypeOf((*ygotnddpstate.Device)(nil)),
 				SchemaTreeRoot:  ygotnddpstate.SchemaTree["Device"],
 				JsonUnmarshaler: ygotnddpstate.Unmarshal,
-				//EnumData:        ygotnddpstate.ΛEnum,
+				EnumData:        ygotnddpstate.ΛEnum,
 			},
 		})
 
@@ -148,6 +210,8 @@ var startCmd = &cobra.Command{
 			Cache:                 c,
 			StateTargetController: stc,
 			Collector:             col,
+			ReconcileDelay:        reconcileDelay,
+			ReconcileBackoff:      backoff,
 		})
 
 		// handles the health with the service discovery
@@ -160,6 +224,11 @@ var startCmd = &cobra.Command{
 			Address: ":" + strconv.Itoa(pkgmetav1.GnmiServerPort),
 			GNMI:    true,
 			Health:  true,
+			// the certificates of the grpc server, the reconciler verifies them with the
+			// ca of its own grpc certificate and presents its own grpc certificate. The
+			// grpc server does not verify client certificates, the channel is not mutually
+			// authenticated until the grpc server supports it.
+			CertDir: grpcCertDir,
 			//Insecure: true,
 		},
 			grpcserver.WithLogger(logger),
@@ -168,6 +237,9 @@ var startCmd = &cobra.Command{
 			grpcserver.WithSetUpdateHandler(origin.State, ssc.Set),
 			grpcserver.WithSetReplaceHandler(origin.State, ssc.Set),
 			grpcserver.WithSetDeleteHandler(origin.State, ssc.Delete),
+			grpcserver.WithGetHandler(lastvalue.Origin, lvc.Get),
+			grpcserver.WithGetHandler(entrystatus.Origin, ssc.GetStatus),
+			grpcserver.WithSubscribeHandler(lvc.Subscribe),
 			grpcserver.WithWatchHandler(ssw.Watch),
 			grpcserver.WithCheckHandler(ssw.Check),
 		)
@@ -253,4 +325,19 @@ func init() {
 	startCmd.Flags().StringVarP(&serviceDiscoveryNamespace, "service-discovery-namespace", "", os.Getenv("SERVICE_DISCOVERY_NAMESPACE"), "the namespace used for service discovery")
 	startCmd.Flags().StringVarP(&serviceDiscoveryDcName, "service-discovery-dc-name", "", os.Getenv("SERVICE_DISCOVERY_DCNAME"), "The dc name used in service discovery")
 	startCmd.Flags().StringVarP(&mqAddress, "mq-address", "", "nats.ndd-system.svc.cluster.local", "comma separated message queue server addresses")
+	startCmd.Flags().StringSliceVarP(&outputs, "outputs", "", []string{collector.OutputKindNATS}, "outputs the collected state is published to: nats, stdout, file or memory")
+	startCmd.Flags().StringVarP(&outputFile, "output-file", "", "", "The file the file output appends the collected state to as json lines.")
+	startCmd.Flags().IntVarP(&queueSize, "queue-size", "", 1000, "The number of messages per target that are queued for the outputs.")
+	startCmd.Flags().IntVarP(&outputQueueSize, "output-queue-size", "", 1000, "The number of messages per output that are queued to be written, messages are dropped when the queue is full.")
+	startCmd.Flags().StringVarP(&overflowPolicy, "queue-overflow-policy", "", string(collector.OverflowPolicyBlock), "What happens when the queue of a target is full: block, drop-oldest or drop-newest.")
+	startCmd.Flags().StringVarP(&messageFormat, "message-format", "", string(collector.MessageFormatSubject), "The format of the published messages: subject, or event which adds the path, the origin and the list keys as tags.")
+	startCmd.Flags().StringVarP(&prometheusAddr, "prometheus-bind-address", "", "", "The address the prometheus endpoint with the collected numeric state binds to, disabled when empty.")
+	startCmd.Flags().DurationVarP(&retryInitialInterval, "retry-initial-interval", "", time.Second, "The wait time before the first retry of a failed target or subscription.")
+	startCmd.Flags().DurationVarP(&retryMaxInterval, "retry-max-interval", "", 5*time.Minute, "The maximum wait time between retries of a failed target or subscription.")
+	startCmd.Flags().Float64VarP(&retryMultiplier, "retry-multiplier", "", 2, "The factor the wait time between retries grows with.")
+	startCmd.Flags().Float64VarP(&retryJitter, "retry-jitter", "", 0.2, "The fraction of the wait time between retries that is randomized.")
+	startCmd.Flags().StringVarP(&grpcCertDir, "grpc-cert-dir", "", os.Getenv("GRPC_CERT_DIR"), "The directory with the ca.crt, tls.crt and tls.key of the grpc server.")
+	startCmd.Flags().DurationVarP(&reconcileDelay, "reconcile-delay", "", stategnmihandler.DefaultReconcileDelay, "The time config changes of a target are coalesced before the collector is reconciled.")
+	startCmd.Flags().StringVarP(&mqTLSSecret, "mq-tls-secret", "", "", "The secret in the pod namespace with the ca.crt, tls.crt and tls.key used to connect to the message queue servers.")
+	startCmd.Flags().StringVarP(&mqCredentialsSecret, "mq-credentials-secret", "", "", "The secret in the pod namespace with the username/password, nkey or creds used to authenticate to the message queue servers.")
 }
@@ -0,0 +1,168 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultBackoffInitial    = time.Second
+	defaultBackoffMax        = 5 * time.Minute
+	defaultBackoffMultiplier = 2
+	defaultBackoffJitter     = 0.2
+
+	// maxBackoffDuration is the wait time when the backoff has no maximum and the wait
+	// time overflows a duration
+	maxBackoffDuration = time.Duration(math.MaxInt64)
+
+	// errors
+	errInvalidBackoff = "invalid backoff"
+)
+
+// Backoff defines the exponential backoff between retries of a target
+type Backoff struct {
+	// Initial is the wait time before the first retry
+	Initial time.Duration
+	// Max is the maximum wait time between retries
+	Max time.Duration
+	// Multiplier is the factor the wait time grows with on every attempt
+	Multiplier float64
+	// Jitter is the fraction of the wait time that is randomized, e.g. 0.2 is +/- 20%
+	Jitter float64
+}
+
+// DefaultBackoff returns the backoff used when none is specified
+func DefaultBackoff() Backoff {
+	return Backoff{
+		Initial:    defaultBackoffInitial,
+		Max:        defaultBackoffMax,
+		Multiplier: defaultBackoffMultiplier,
+		Jitter:     defaultBackoffJitter,
+	}
+}
+
+// Validate returns an error if the backoff cannot be used to retry
+func (b Backoff) Validate() error {
+	switch {
+	case b.Initial <= 0:
+		return errors.Errorf("%s: initial interval %s must be positive", errInvalidBackoff, b.Initial)
+	case b.Max < 0:
+		return errors.Errorf("%s: max interval %s must not be negative", errInvalidBackoff, b.Max)
+	case b.Max > 0 && b.Max < b.Initial:
+		return errors.Errorf("%s: max interval %s is less than the initial interval %s", errInvalidBackoff, b.Max, b.Initial)
+	case b.Multiplier < 1:
+		return errors.Errorf("%s: multiplier %v must be at least 1", errInvalidBackoff, b.Multiplier)
+	case b.Jitter < 0 || b.Jitter > 1:
+		return errors.Errorf("%s: jitter %v must be between 0 and 1", errInvalidBackoff, b.Jitter)
+	}
+	return nil
+}
+
+// Duration returns the wait time before retry attempt, attempts start at 1. The wait
+// time never shrinks nor becomes negative, also for a backoff that does not validate.
+func (b Backoff) Duration(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	multiplier := math.Max(b.Multiplier, 1)
+	jitter := math.Min(math.Max(b.Jitter, 0), 1)
+
+	d := float64(b.Initial) * math.Pow(multiplier, float64(attempt-1))
+	if jitter > 0 {
+		d += d * jitter * (2*rand.Float64() - 1)
+	}
+	// the jitter does not extend the wait beyond the maximum
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	switch {
+	case d < 0:
+		return 0
+	case d >= float64(maxBackoffDuration):
+		return maxBackoffDuration
+	}
+	return time.Duration(d)
+}
+
+// RetryState is the retry state of a target or a subscription
+type RetryState struct {
+	// Attempt is the number of consecutive failed attempts, 0 if the last attempt succeeded
+	Attempt int
+	// NextRetry is the time of the next attempt
+	NextRetry time.Time
+	// LastError is the error of the last failed attempt
+	LastError string
+	// LastErrorTime is the time of the last failed attempt
+	LastErrorTime time.Time
+}
+
+// retryState tracks the retries of a target or a subscription, failures that happen
+// before the scheduled retry, e.g. the errors of several subscriptions that lost the
+// connection to the same target, are retried together
+type retryState struct {
+	m       sync.RWMutex
+	backoff Backoff
+	state   RetryState
+}
+
+func newRetryState(b Backoff) *retryState {
+	return &retryState{backoff: b}
+}
+
+// failure records a failed attempt and returns the time of the next retry
+func (r *retryState) failure(err error) time.Time {
+	r.m.Lock()
+	defer r.m.Unlock()
+	now := time.Now()
+	if !now.Before(r.state.NextRetry) {
+		r.state.Attempt++
+		r.state.NextRetry = now.Add(r.backoff.Duration(r.state.Attempt))
+	}
+	r.state.LastError = err.Error()
+	r.state.LastErrorTime = now
+	return r.state.NextRetry
+}
+
+// success resets the retry state after a successful attempt
+func (r *retryState) success() {
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.state.Attempt = 0
+	r.state.NextRetry = time.Time{}
+}
+
+// wait returns the time until the next retry, 0 if a retry is allowed
+func (r *retryState) wait() time.Duration {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	if d := time.Until(r.state.NextRetry); d > 0 {
+		return d
+	}
+	return 0
+}
+
+func (r *retryState) get() RetryState {
+	r.m.RLock()
+	defer r.m.RUnlock()
+	return r.state
+}
@@ -0,0 +1,119 @@
+package collector
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBackoffDuration(t *testing.T) {
+	b := Backoff{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 0, want: time.Second},
+		{attempt: 1, want: time.Second},
+		{attempt: 3, want: 4 * time.Second},
+		{attempt: 10, want: 10 * time.Second},
+	}
+	for _, tt := range tests {
+		if got := b.Duration(tt.attempt); got != tt.want {
+			t.Errorf("Duration(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := b.Duration(3); got < 2*time.Second || got > 6*time.Second {
+			t.Fatalf("Duration(3) with jitter = %s, want between 2s and 6s", got)
+		}
+		if got := b.Duration(10); got > b.Max {
+			t.Fatalf("Duration(10) with jitter = %s, want at most %s", got, b.Max)
+		}
+	}
+}
+
+func TestBackoffDurationBounds(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       Backoff
+		attempt int
+		min     time.Duration
+		max     time.Duration
+	}{
+		{
+			name:    "jitter above 1 is not negative",
+			b:       Backoff{Initial: time.Second, Multiplier: 1, Jitter: 5},
+			attempt: 1,
+			min:     0,
+			max:     2 * time.Second,
+		},
+		{
+			name:    "multiplier below 1 does not shrink",
+			b:       Backoff{Initial: time.Second, Multiplier: 0.5},
+			attempt: 5,
+			min:     time.Second,
+			max:     time.Second,
+		},
+		{
+			name:    "no max does not overflow",
+			b:       Backoff{Initial: time.Second, Multiplier: 2},
+			attempt: 1000,
+			min:     maxBackoffDuration,
+			max:     maxBackoffDuration,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := tt.b.Duration(tt.attempt); got < tt.min || got > tt.max {
+					t.Fatalf("Duration(%d) = %s, want between %s and %s", tt.attempt, got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		b       Backoff
+		wantErr bool
+	}{
+		{name: "default", b: DefaultBackoff()},
+		{name: "no max", b: Backoff{Initial: time.Second, Multiplier: 2}},
+		{name: "no initial", b: Backoff{Multiplier: 2}, wantErr: true},
+		{name: "max below initial", b: Backoff{Initial: time.Minute, Max: time.Second, Multiplier: 2}, wantErr: true},
+		{name: "multiplier below 1", b: Backoff{Initial: time.Second, Multiplier: 0.5}, wantErr: true},
+		{name: "negative jitter", b: Backoff{Initial: time.Second, Multiplier: 2, Jitter: -0.1}, wantErr: true},
+		{name: "jitter above 1", b: Backoff{Initial: time.Second, Multiplier: 2, Jitter: 1.5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.b.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRetryState(t *testing.T) {
+	r := newRetryState(Backoff{Initial: time.Minute, Multiplier: 2})
+	next := r.failure(errors.New("first"))
+	// a failure before the scheduled retry is retried together with the first one
+	if got := r.failure(errors.New("second")); !got.Equal(next) {
+		t.Errorf("failure() = %s, want %s", got, next)
+	}
+	s := r.get()
+	if s.Attempt != 1 || s.LastError != "second" {
+		t.Errorf("get() = %+v, want attempt 1 and last error second", s)
+	}
+	if r.wait() <= 0 {
+		t.Errorf("wait() = %s, want > 0", r.wait())
+	}
+	r.success()
+	if s := r.get(); s.Attempt != 0 || r.wait() != 0 {
+		t.Errorf("get() after success() = %+v, want attempt 0 and no wait", s)
+	}
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// encodings a subscription is downgraded to in order of preference
+var encodingPreference = []gnmi.Encoding{
+	gnmi.Encoding_JSON_IETF,
+	gnmi.Encoding_JSON,
+	gnmi.Encoding_PROTO,
+	gnmi.Encoding_ASCII,
+}
+
+// capabilities are the models and encodings a target supports, a target that does
+// not report its capabilities is assumed to support every model and encoding
+type capabilities struct {
+	encodings map[gnmi.Encoding]struct{}
+	models    map[string]struct{}
+}
+
+func newCapabilities(resp *gnmi.CapabilityResponse) *capabilities {
+	c := &capabilities{
+		encodings: map[gnmi.Encoding]struct{}{},
+		models:    map[string]struct{}{},
+	}
+	for _, e := range resp.GetSupportedEncodings() {
+		c.encodings[e] = struct{}{}
+	}
+	for _, m := range resp.GetSupportedModels() {
+		c.models[m.GetName()] = struct{}{}
+	}
+	return c
+}
+
+func (c *capabilities) supportsEncoding(e gnmi.Encoding) bool {
+	if c == nil || len(c.encodings) == 0 {
+		return true
+	}
+	_, ok := c.encodings[e]
+	return ok
+}
+
+func (c *capabilities) supportsModel(name string) bool {
+	if c == nil || len(c.models) == 0 {
+		return true
+	}
+	_, ok := c.models[name]
+	return ok
+}
+
+// check validates the subscription settings of a state entry against the capabilities
+// of the target. It returns the encoding to subscribe with and a message if the encoding
+// was downgraded, or an error if the state entry cannot be subscribed to.
+func (c *capabilities) check(s *Subscription) (string, string, error) {
+	for _, p := range s.StateEntry.Path {
+		for _, pe := range yparser.Xpath2GnmiPath(p, 0).GetElem() {
+			// elements qualified with a module name, e.g. srl_nokia-interfaces:interface
+			i := strings.Index(pe.GetName(), ":")
+			if i <= 0 {
+				continue
+			}
+			if model := pe.GetName()[:i]; !c.supportsModel(model) {
+				return "", "", fmt.Errorf("path %s: model %s not supported by the target", p, model)
+			}
+		}
+	}
+
+	encoding := s.GetEncoding()
+	e, ok := gnmi.Encoding_value[strings.ToUpper(encoding)]
+	if !ok {
+		return "", "", fmt.Errorf("unknown encoding %s", encoding)
+	}
+	if c.supportsEncoding(gnmi.Encoding(e)) {
+		return encoding, "", nil
+	}
+	for _, fb := range encodingPreference {
+		if c.supportsEncoding(fb) {
+			fallback := strings.ToLower(fb.String())
+			return fallback, fmt.Sprintf("encoding %s not supported by the target, using %s", encoding, fallback), nil
+		}
+	}
+	return "", "", fmt.Errorf("encoding %s not supported by the target", encoding)
+}
+
+// isUnsupported returns true if the target rejected a subscription because it does
+// not support the requested subscription, e.g. on-change for a path
+func isUnsupported(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	switch st.Code() {
+	case codes.Unimplemented, codes.InvalidArgument:
+		return true
+	}
+	return false
+}
+
+// isTransportFailure returns true if the error reports that the connection to the
+// target failed, as opposed to a failure of a single subscription
+func isTransportFailure(err error) bool {
+	st, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+	return st.Code() == codes.Unavailable
+}
@@ -0,0 +1,75 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+func TestCapabilitiesCheck(t *testing.T) {
+	caps := newCapabilities(&gnmi.CapabilityResponse{
+		SupportedModels:    []*gnmi.ModelData{{Name: "srl_nokia-interfaces"}},
+		SupportedEncodings: []gnmi.Encoding{gnmi.Encoding_JSON_IETF, gnmi.Encoding_PROTO},
+	})
+	tests := []struct {
+		name         string
+		caps         *capabilities
+		se           *ygotnddpstate.YnddState_StateEntry
+		wantEncoding string
+		wantMsg      bool
+		wantErr      bool
+	}{
+		{
+			name: "supported",
+			caps: caps,
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name:     strPtr("itfce"),
+				Path:     []string{"/srl_nokia-interfaces:interface[name=*]/oper-state"},
+				Encoding: ygotnddpstate.YnddState_StateEntry_Encoding_proto,
+			},
+			wantEncoding: "proto",
+		},
+		{
+			name: "encoding downgraded",
+			caps: caps,
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name: strPtr("itfce"),
+				Path: []string{"/interface[name=*]/oper-state"},
+			},
+			wantEncoding: "json_ietf",
+			wantMsg:      true,
+		},
+		{
+			name: "model not supported",
+			caps: caps,
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name: strPtr("itfce"),
+				Path: []string{"/openconfig-interfaces:interfaces/interface[name=*]/state"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "capabilities unknown",
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name: strPtr("itfce"),
+				Path: []string{"/openconfig-interfaces:interfaces/interface[name=*]/state"},
+			},
+			wantEncoding: "ascii",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoding, msg, err := tt.caps.check(NewSubscription(tt.se))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if encoding != tt.wantEncoding {
+				t.Errorf("check() encoding = %q, want %q", encoding, tt.wantEncoding)
+			}
+			if (msg != "") != tt.wantMsg {
+				t.Errorf("check() msg = %q, wantMsg %v", msg, tt.wantMsg)
+			}
+		})
+	}
+}
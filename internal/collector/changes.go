@@ -0,0 +1,91 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// transition message tags of state entries that publish changes only
+	tagPreviousValue          = "previous-value"
+	tagPreviousValueType      = "previous-value-type"
+	tagTransitionTime         = "transition-time"
+	tagPreviousTransitionTime = "previous-transition-time"
+)
+
+// trackedValue is the last published value of a path
+type trackedValue struct {
+	data      []byte
+	valueType string
+	// timestamp of the notification the value changed in
+	timestamp int64
+}
+
+// changeTracker keeps the last published value per path of a state entry such that
+// values a target resends, e.g. after a reconnect or a sync, are not published again.
+// It outlives the restarts of the subscription.
+type changeTracker struct {
+	m      sync.Mutex
+	values map[string]trackedValue
+}
+
+func newChangeTracker() *changeTracker {
+	return &changeTracker{values: map[string]trackedValue{}}
+}
+
+// update records the value of the path at xpath and returns the previous value,
+// whether there was a previous value and whether the value changed
+func (t *changeTracker) update(xpath string, v trackedValue) (trackedValue, bool, bool) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	prev, ok := t.values[xpath]
+	if ok && prev.valueType == v.valueType && bytes.Equal(prev.data, v.data) {
+		return prev, true, false
+	}
+	t.values[xpath] = v
+	return prev, ok, true
+}
+
+// delete removes the values of the path at xpath and its children, the next value
+// of a deleted path is published as a change
+func (t *changeTracker) delete(xpath string) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	for k := range t.values {
+		if k == xpath || strings.HasPrefix(k, xpath+"/") || strings.HasPrefix(k, xpath+"[") {
+			delete(t.values, k)
+		}
+	}
+}
+
+// transitionTags returns the tags of a message for the change from the previous value
+// to the current value
+func transitionTags(prev trackedValue, hasPrev bool, cur trackedValue) map[string]string {
+	tags := map[string]string{
+		tagTransitionTime: time.Unix(0, cur.timestamp).UTC().Format(time.RFC3339Nano),
+	}
+	if hasPrev {
+		tags[tagPreviousValue] = string(prev.data)
+		tags[tagPreviousValueType] = prev.valueType
+		tags[tagPreviousTransitionTime] = time.Unix(0, prev.timestamp).UTC().Format(time.RFC3339Nano)
+	}
+	return tags
+}
@@ -0,0 +1,37 @@
+package collector
+
+import "testing"
+
+func TestChangeTracker(t *testing.T) {
+	ct := newChangeTracker()
+	up := trackedValue{data: []byte("up"), valueType: "string", timestamp: 1}
+	down := trackedValue{data: []byte("down"), valueType: "string", timestamp: 2}
+	path := "/interface[name=e1]/oper-state"
+
+	steps := []struct {
+		name        string
+		value       trackedValue
+		delete      bool
+		wantChanged bool
+		wantPrev    string
+	}{
+		{name: "First", value: up, wantChanged: true},
+		{name: "Resent", value: up, wantChanged: false, wantPrev: "up"},
+		{name: "Changed", value: down, wantChanged: true, wantPrev: "up"},
+		{name: "DeleteParent", delete: true},
+		{name: "AfterDelete", value: down, wantChanged: true},
+	}
+	for _, step := range steps {
+		if step.delete {
+			ct.delete("/interface[name=e1]")
+			continue
+		}
+		prev, hasPrev, changed := ct.update(path, step.value)
+		if changed != step.wantChanged {
+			t.Errorf("%s: changed = %t, want %t", step.name, changed, step.wantChanged)
+		}
+		if got := string(prev.data); hasPrev != (step.wantPrev != "") || got != step.wantPrev {
+			t.Errorf("%s: previous = %q, want %q", step.name, got, step.wantPrev)
+		}
+	}
+}
@@ -10,6 +10,9 @@ import (
 	"github.com/yndd/cache/pkg/origin"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/ndd-runtime/pkg/meta"
+	"github.com/yndd/state/internal/lastvalue"
+	"github.com/yndd/state/internal/promexporter"
+	"github.com/yndd/state/pkg/entrystatus"
 	"github.com/yndd/state/pkg/ygotnddpstate"
 )
 
@@ -21,14 +24,28 @@ type Collector interface {
 	WithLogger(log logging.Logger)
 	// add a cache to Collector
 	WithCache(c cache.Cache)
-	// add the mq address
-	WithMQAddress(addr string)
+	// add the outputs the collected state is published to
+	WithOutputs(o []Output)
+	// add the size and overflow policy of the per target queue
+	WithQueue(size int, policy OverflowPolicy)
+	// add the format of the published messages
+	WithMessageFormat(f MessageFormat)
+	// add the cache that keeps the last collected values
+	WithLastValueCache(lvc lastvalue.Cache)
+	// add the exporter that exposes the collected numeric values as prometheus metrics
+	WithExporter(e promexporter.Exporter)
+	// add the backoff used to retry failed targets and subscriptions
+	WithBackoff(b Backoff)
 	// check if a target exists
 	IsActive(target string) bool
 	// start target collector
 	ReconcileTarget(tc *types.TargetConfig) error
 	// stop target collector
 	StopTarget(target string) error
+	// get the status of the state entries of a target
+	GetEntryStatus(target string) (map[string]entrystatus.EntryStatus, error)
+	// get the reachability of a target
+	GetTargetStatus(target string) entrystatus.TargetStatus
 	// stop all target collectors
 	Stop() error
 }
@@ -47,10 +64,46 @@ func WithCache(c cache.Cache) Option {
 	}
 }
 
-// WithLogger specifies how the collector logs messages.
-func WithMQAddress(addr string) Option {
+// WithOutputs specifies the outputs the collected state is published to.
+func WithOutputs(o ...Output) Option {
+	return func(d Collector) {
+		d.WithOutputs(o)
+	}
+}
+
+// WithQueue specifies the size and the overflow policy of the per target queue
+// between the gnmi receive loop and the publisher.
+func WithQueue(size int, policy OverflowPolicy) Option {
+	return func(d Collector) {
+		d.WithQueue(size, policy)
+	}
+}
+
+// WithMessageFormat specifies the format of the published messages.
+func WithMessageFormat(f MessageFormat) Option {
+	return func(d Collector) {
+		d.WithMessageFormat(f)
+	}
+}
+
+// WithLastValueCache specifies the cache that keeps the last collected values per target.
+func WithLastValueCache(lvc lastvalue.Cache) Option {
+	return func(d Collector) {
+		d.WithLastValueCache(lvc)
+	}
+}
+
+// WithExporter specifies the exporter that exposes the collected numeric values as prometheus metrics.
+func WithExporter(e promexporter.Exporter) Option {
 	return func(d Collector) {
-		d.WithMQAddress(addr)
+		d.WithExporter(e)
+	}
+}
+
+// WithBackoff specifies the backoff used to retry failed targets and subscriptions.
+func WithBackoff(b Backoff) Option {
+	return func(d Collector) {
+		d.WithBackoff(b)
 	}
 }
 
@@ -62,14 +115,24 @@ type collector struct {
 	cache            cache.Cache
 	ctx              context.Context
 	cfn              context.CancelFunc
-	mqAddr           string
-	log              logging.Logger
+	outputs          []Output
+	queueSize        int
+	overflowPolicy   OverflowPolicy
+	messageFormat    MessageFormat
+	lastValueCache   lastvalue.Cache
+	exporter         promexporter.Exporter
+	backoff          Backoff
+	// retry states indexed by target name, they outlive the target collectors
+	retries map[string]*retryState
+	log     logging.Logger
 }
 
 // New creates a new Collector interface
 func New(ctx context.Context, opts ...Option) Collector {
 	c := &collector{
 		targetCollectors: map[string]TargetCollector{},
+		backoff:          DefaultBackoff(),
+		retries:          map[string]*retryState{},
 	}
 	for _, opt := range opts {
 		opt(c)
@@ -86,8 +149,91 @@ func (c *collector) WithCache(cache cache.Cache) {
 	c.cache = cache
 }
 
-func (c *collector) WithMQAddress(addr string) {
-	c.mqAddr = addr
+func (c *collector) WithOutputs(o []Output) {
+	c.outputs = o
+}
+
+func (c *collector) WithQueue(size int, policy OverflowPolicy) {
+	c.queueSize = size
+	c.overflowPolicy = policy
+}
+
+func (c *collector) WithMessageFormat(f MessageFormat) {
+	c.messageFormat = f
+}
+
+func (c *collector) WithLastValueCache(lvc lastvalue.Cache) {
+	c.lastValueCache = lvc
+}
+
+func (c *collector) WithExporter(e promexporter.Exporter) {
+	c.exporter = e
+}
+
+func (c *collector) WithBackoff(b Backoff) {
+	c.backoff = b
+}
+
+// GetEntryStatus returns the status of every state entry in the running config of the
+// target, state entries of a target that could not be connected report the error of
+// the target
+func (c *collector) GetEntryStatus(target string) (map[string]entrystatus.EntryStatus, error) {
+	cacheNsTargetName := meta.NamespacedName(target).GetPrefixNamespacedName(origin.State)
+	ce, err := c.cache.GetEntry(cacheNsTargetName)
+	if err != nil {
+		return nil, err
+	}
+	runningConfig, ok := ce.GetRunningConfig().(*ygotnddpstate.Device)
+	if !ok {
+		return nil, errors.New("unexpected Object")
+	}
+
+	c.m.Lock()
+	defer c.m.Unlock()
+	var subStatus map[string]entrystatus.EntryStatus
+	if tColl, ok := c.targetCollectors[target]; ok {
+		subStatus = tColl.GetEntryStatus()
+	}
+	var rs RetryState
+	if r, ok := c.retries[target]; ok {
+		rs = r.get()
+	}
+
+	es := make(map[string]entrystatus.EntryStatus, len(runningConfig.StateEntry))
+	for name := range runningConfig.StateEntry {
+		s, ok := subStatus[name]
+		if !ok {
+			s = entrystatus.EntryStatus{Name: name}
+			if rs.Attempt > 0 {
+				s.LastError = rs.LastError
+				s.LastErrorTime = rs.LastErrorTime
+			}
+		}
+		es[name] = s
+	}
+	return es, nil
+}
+
+// GetTargetStatus returns the reachability of a target, a target is reachable when its
+// target collector is connected and the connection did not fail since, the failures of
+// single subscriptions do not affect the reachability
+func (c *collector) GetTargetStatus(target string) entrystatus.TargetStatus {
+	c.m.Lock()
+	defer c.m.Unlock()
+	ts := entrystatus.TargetStatus{Target: target}
+	tColl, active := c.targetCollectors[target]
+	var rs RetryState
+	if r, ok := c.retries[target]; ok {
+		rs = r.get()
+	}
+	ts.Reachable = active && tColl.IsConnected() && rs.Attempt == 0
+	// the target collector is not created or connected yet
+	ts.Pending = !ts.Reachable && rs.Attempt == 0
+	ts.Attempt = rs.Attempt
+	ts.NextRetry = rs.NextRetry
+	ts.LastError = rs.LastError
+	ts.LastErrorTime = rs.LastErrorTime
+	return ts
 }
 
 func (c *collector) IsActive(target string) bool {
@@ -113,11 +259,19 @@ func (c *collector) ReconcileTarget(tc *types.TargetConfig) error {
 		return errors.New("unexpected Object")
 	}
 	log.Debug("Collector", "Running config", runningConfig)
+
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	tColl, ok := c.targetCollectors[tc.Name]
 	// validate if there are still state entries in the config, if not we should delete the target
 	if len(runningConfig.StateEntry) == 0 {
-		if c.IsActive(tc.Name) {
-			err = c.StopTarget(tc.Name)
-			if err != nil {
+		delete(c.retries, tc.Name)
+		if !ok {
+			deleteTargetMetrics(tc.Name, nil)
+		} else {
+			delete(c.targetCollectors, tc.Name)
+			if err := c.stopTargetCollector(tc.Name, tColl); err != nil {
 				return err
 			}
 		}
@@ -125,45 +279,72 @@ func (c *collector) ReconcileTarget(tc *types.TargetConfig) error {
 		return nil
 	}
 
-	if c.IsActive(tc.Name) {
-		log.Debug("handleSet", "Active", true)
-		if err := c.StopTarget(tc.Name); err != nil {
-			log.Debug("handleSet", "Stop success", false)
+	retry, ok := c.retries[tc.Name]
+	if !ok {
+		retry = newRetryState(c.backoff)
+		c.retries[tc.Name] = retry
+	}
+
+	tColl, ok = c.targetCollectors[tc.Name]
+	if ok && !tColl.HasConnectionConfig(tc) {
+		// the gnmi client is only recreated when the connection parameters of the target change,
+		// changes of the state entries are applied to the running target collector
+		log.Debug("target connection config changed, reconnecting")
+		delete(c.targetCollectors, tc.Name)
+		if err := tColl.Stop(); err != nil {
 			return err
 		}
-		c.log.Debug("handleSet", "Stop success", true)
+		ok = false
 	}
-	log.Debug("handleUpdate with running config", "runningConfig", runningConfig)
-
-	// create a new target collector
-	tColl, err := NewTargetCollector(c.ctx, tc, runningConfig,
-		WithTargetCollectorLogger(c.log),
-		WithTargetCollectorMQAddr(c.mqAddr),
-	)
-	if err != nil {
-		return err
+	if !ok {
+		// create a new target collector, it connects to the target in the background and
+		// retries a failed dial with the backoff of the target
+		tColl = NewTargetCollector(c.ctx, tc,
+			WithTargetCollectorLogger(c.log),
+			WithTargetCollectorOutputs(c.outputs),
+			WithTargetCollectorQueue(c.queueSize, c.overflowPolicy),
+			WithTargetCollectorMessageFormat(c.messageFormat),
+			WithTargetCollectorLastValueCache(c.lastValueCache),
+			WithTargetCollectorExporter(c.exporter),
+			withTargetCollectorRetryState(retry),
+		)
+		c.targetCollectors[tc.Name] = tColl
+		if err := tColl.Start(c.ctx); err != nil {
+			return err
+		}
 	}
+	log.Debug("handleUpdate with running config", "runningConfig", runningConfig)
 
-	c.m.Lock()
-	defer c.m.Unlock()
-	c.targetCollectors[tc.Name] = tColl
-	if err := tColl.Start(c.ctx); err != nil {
-		return err
-	}
-	return nil
+	// only the subscriptions of the state entries that changed are started/stopped
+	return tColl.ReconcileSubscriptions(runningConfig)
 }
 
 func (c *collector) StopTarget(target string) error {
 	c.m.Lock()
 	defer c.m.Unlock()
 
+	delete(c.retries, target)
 	tColl, ok := c.targetCollectors[target]
 	if !ok {
+		// the dial failures of a target that never connected
+		deleteTargetMetrics(target, nil)
 		return nil
 	}
 	// delete state collector
 	delete(c.targetCollectors, target)
-	return tColl.Stop()
+	return c.stopTargetCollector(target, tColl)
+}
+
+// stopTargetCollector stops the target collector of a target that is removed and deletes
+// its metrics, the metrics are kept when the target collector is only reconnected
+func (c *collector) stopTargetCollector(target string, tColl TargetCollector) error {
+	subs := []string{}
+	for name := range tColl.GetEntryStatus() {
+		subs = append(subs, name)
+	}
+	err := tColl.Stop()
+	deleteTargetMetrics(target, subs)
+	return err
 }
 
 func (c *collector) Stop() error {
@@ -177,5 +358,10 @@ func (c *collector) Stop() error {
 			c.log.Debug("failed to stop target collector", "target", target, "error", err)
 		}
 	}
+	for _, o := range c.outputs {
+		if err := o.Close(); err != nil {
+			c.log.Debug("failed to close output", "error", err)
+		}
+	}
 	return nil
 }
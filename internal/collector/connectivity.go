@@ -0,0 +1,181 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yndd/pubsub"
+	statesubject "github.com/yndd/state/pkg/subject"
+)
+
+// ConnectivityEvent is an event of the gnmi stream of a state entry or of the connection
+// to a target
+type ConnectivityEvent string
+
+const (
+	// ConnectivityConnected is published when the first response of a (re)started stream is
+	// received, for a target when the gnmi client is created or a stream of the target
+	// receives a response after the connection was lost
+	ConnectivityConnected ConnectivityEvent = "connected"
+	// ConnectivityDisconnected is published when the stream fails, the values are no longer
+	// current. For a target it is published when the dial fails, the connection to the
+	// target is lost or the target collector is stopped, e.g. when it is recreated after
+	// the connection parameters of the target changed.
+	ConnectivityDisconnected ConnectivityEvent = "disconnected"
+	// ConnectivityResynced is published when the stream completed the sync after a disconnect,
+	// for a target when all its streams completed the sync after the target was disconnected
+	ConnectivityResynced ConnectivityEvent = "resynced"
+
+	// subject token of the connectivity events, e.g. nddpstate.leaf1._connectivity.itfce
+	// for a state entry and nddpstate.leaf1._connectivity for the target
+	connectivitySubjectToken = "_connectivity"
+
+	// connectivity event tags
+	tagEvent = "event"
+	tagError = "error"
+
+	// errors
+	errTargetCollectorStopped = "target collector stopped"
+)
+
+// connectivityMsg returns the message of a connectivity event of the stream of a state entry
+func connectivityMsg(targetName string, s *Subscription, event ConnectivityEvent, err error) *pubsub.Msg {
+	sm := &pubsub.Msg{
+		Subject: fmt.Sprintf("%s.%s.%s.%s", streamName, statesubject.SanitizeToken(targetName),
+			connectivitySubjectToken, statesubject.SanitizeToken(s.GetName())),
+		Timestamp: time.Now().UnixNano(),
+		Operation: pubsub.Operation_OPERATION_UPDATE,
+		Data:      []byte(event),
+		Tags:      stateEntryTags(targetName, s),
+	}
+	sm.Tags[tagEvent] = string(event)
+	if err != nil {
+		sm.Tags[tagError] = err.Error()
+	}
+	return sm
+}
+
+// publishConnectivity queues a connectivity event of the stream of a state entry
+func (c *targetCollector) publishConnectivity(s *Subscription, event ConnectivityEvent, err error) error {
+	c.log.Debug("connectivity", "target", c.target.Config.Name, "subscription", s.GetName(), "event", event)
+	return c.queue.push(c.ctx, connectivityMsg(c.target.Config.Name, s, event, err))
+}
+
+// targetConnectivityMsg returns the message of a connectivity event of a target
+func targetConnectivityMsg(targetName string, event ConnectivityEvent, err error) *pubsub.Msg {
+	sm := &pubsub.Msg{
+		Subject:   fmt.Sprintf("%s.%s.%s", streamName, statesubject.SanitizeToken(targetName), connectivitySubjectToken),
+		Timestamp: time.Now().UnixNano(),
+		Operation: pubsub.Operation_OPERATION_UPDATE,
+		Data:      []byte(event),
+		Tags: map[string]string{
+			tagTarget: targetName,
+			tagEvent:  string(event),
+		},
+	}
+	if err != nil {
+		sm.Tags[tagError] = err.Error()
+	}
+	return sm
+}
+
+// targetConnectivity is the connectivity of a target as published in its events, only
+// the events that change it are published
+type targetConnectivity struct {
+	m sync.Mutex
+	// last published event, empty before the first one
+	event ConnectivityEvent
+	// resync is set when the target is disconnected, until all its streams synced again
+	resync bool
+}
+
+// transition records an event of the target, it returns false if the event does not
+// change the connectivity of the target and is not published
+func (tc *targetConnectivity) transition(event ConnectivityEvent) bool {
+	tc.m.Lock()
+	defer tc.m.Unlock()
+	switch event {
+	case ConnectivityConnected:
+		if tc.event != "" && tc.event != ConnectivityDisconnected {
+			return false
+		}
+	case ConnectivityDisconnected:
+		if tc.event == ConnectivityDisconnected {
+			return false
+		}
+		tc.resync = true
+	case ConnectivityResynced:
+		if !tc.resync || tc.event != ConnectivityConnected {
+			return false
+		}
+		tc.resync = false
+	}
+	tc.event = event
+	return true
+}
+
+// stop records that the target collector stops, it returns true if the target was
+// connected and the disconnected event is published
+func (tc *targetConnectivity) stop() bool {
+	tc.m.Lock()
+	defer tc.m.Unlock()
+	if tc.event != ConnectivityConnected && tc.event != ConnectivityResynced {
+		return false
+	}
+	tc.event = ConnectivityDisconnected
+	return true
+}
+
+// publishTargetConnectivity queues a connectivity event of the target
+func (c *targetCollector) publishTargetConnectivity(event ConnectivityEvent, err error) error {
+	if !c.connectivity.transition(event) {
+		return nil
+	}
+	c.log.Debug("target connectivity", "target", c.target.Config.Name, "event", event)
+	return c.queue.push(c.ctx, targetConnectivityMsg(c.target.Config.Name, event, err))
+}
+
+// publishTargetStopped writes the disconnected event of a connected target that is
+// stopped, the event is written to the outputs directly since the publisher stops
+func (c *targetCollector) publishTargetStopped() {
+	if !c.connectivity.stop() {
+		return
+	}
+	c.log.Debug("target connectivity", "target", c.target.Config.Name, "event", ConnectivityDisconnected)
+	ctx, cancel := context.WithTimeout(context.Background(), defaultTimeout)
+	defer cancel()
+	c.write(ctx, targetConnectivityMsg(c.target.Config.Name, ConnectivityDisconnected, errors.New(errTargetCollectorStopped)))
+}
+
+// resyncTarget publishes the resynced event of the target once all its subscriptions
+// that are not rejected completed the sync
+func (c *targetCollector) resyncTarget() error {
+	c.m.RLock()
+	for _, s := range c.subscriptions {
+		if st := s.GetStatus(); !st.Rejected && !st.Synced {
+			c.m.RUnlock()
+			return nil
+		}
+	}
+	c.m.RUnlock()
+	return c.publishTargetConnectivity(ConnectivityResynced, nil)
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+// canonicalStateEntry is the normalized form of a state entry, state entries that
+// result in the same subscription and processing have the same canonical form
+type canonicalStateEntry struct {
+	Name              string   `json:"name"`
+	Prefix            string   `json:"prefix,omitempty"`
+	Paths             []string `json:"paths"`
+	Mode              int64    `json:"mode"`
+	Encoding          string   `json:"encoding"`
+	SampleInterval    string   `json:"sample-interval,omitempty"`
+	HeartbeatInterval string   `json:"heartbeat-interval,omitempty"`
+	SuppressRedundant bool     `json:"suppress-redundant,omitempty"`
+	ChangesOnly       bool     `json:"changes-only,omitempty"`
+	StaleAfter        string   `json:"stale-after,omitempty"`
+	DeleteAfterResync bool     `json:"delete-after-resync,omitempty"`
+	Prometheus        bool     `json:"prometheus"`
+	MetricName        string   `json:"metric-name,omitempty"`
+	LabelKeys         []string `json:"label-keys,omitempty"`
+	// processors in the order they are applied, the index itself does not matter
+	Processors []canonicalProcessor `json:"processors,omitempty"`
+}
+
+// canonicalProcessor is the normalized form of a processor of a state entry
+type canonicalProcessor struct {
+	Type        int64             `json:"type"`
+	PathMatch   string            `json:"path-match,omitempty"`
+	ValueMatch  string            `json:"value-match,omitempty"`
+	Replacement string            `json:"replacement,omitempty"`
+	ConvertTo   int64             `json:"convert-to,omitempty"`
+	ValueMap    map[string]string `json:"value-map,omitempty"`
+	Tags        map[string]string `json:"tags,omitempty"`
+}
+
+// fingerprint returns a hash of the canonical form of a state entry
+func fingerprint(se *ygotnddpstate.YnddState_StateEntry) string {
+	c := canonicalStateEntry{
+		Prometheus: true,
+	}
+	if se.Name != nil {
+		c.Name = *se.Name
+	}
+	if se.Prefix != nil {
+		c.Prefix = *se.Prefix
+	}
+	// the order of the paths does not change the subscription
+	c.Paths = make([]string, 0, len(se.Path))
+	for _, p := range se.Path {
+		c.Paths = append(c.Paths, yparser.GnmiPath2XPath(yparser.Xpath2GnmiPath(strings.TrimSpace(p), 0), true))
+	}
+	sort.Strings(c.Paths)
+	// on-change is the default mode
+	c.Mode = int64(se.Mode)
+	if se.Mode == ygotnddpstate.YnddState_StateEntry_Mode_UNSET {
+		c.Mode = int64(ygotnddpstate.YnddState_StateEntry_Mode_on_change)
+	}
+	c.Encoding = (&Subscription{StateEntry: se}).GetEncoding()
+	c.SampleInterval = canonicalDuration(se.SampleInterval)
+	c.HeartbeatInterval = canonicalDuration(se.HeartbeatInterval)
+	c.SuppressRedundant = se.SuppressRedundant != nil && *se.SuppressRedundant
+	c.ChangesOnly = se.ChangesOnly != nil && *se.ChangesOnly
+	c.StaleAfter = canonicalDuration(se.StaleAfter)
+	c.DeleteAfterResync = se.DeleteAfterResync != nil && *se.DeleteAfterResync
+	if pc := se.Prometheus; pc != nil {
+		c.Prometheus = pc.Enabled == nil || *pc.Enabled
+		if pc.MetricName != nil {
+			c.MetricName = *pc.MetricName
+		}
+		c.LabelKeys = append([]string{}, pc.LabelKey...)
+		sort.Strings(c.LabelKeys)
+	}
+	c.Processors = canonicalProcessors(se)
+	b, err := json.Marshal(c)
+	if err != nil {
+		// cannot happen for the canonical form, an empty fingerprint never matches
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// canonicalProcessors returns the processors of a state entry in the order of their index
+func canonicalProcessors(se *ygotnddpstate.YnddState_StateEntry) []canonicalProcessor {
+	if len(se.Processor) == 0 {
+		return nil
+	}
+	indexes := make([]uint32, 0, len(se.Processor))
+	for idx := range se.Processor {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+	cps := make([]canonicalProcessor, 0, len(indexes))
+	for _, idx := range indexes {
+		p := se.Processor[idx]
+		cp := canonicalProcessor{
+			Type:      int64(p.Type),
+			ConvertTo: int64(p.ConvertTo),
+		}
+		if p.PathMatch != nil {
+			cp.PathMatch = *p.PathMatch
+		}
+		if p.ValueMatch != nil {
+			cp.ValueMatch = *p.ValueMatch
+		}
+		if p.Replacement != nil {
+			cp.Replacement = *p.Replacement
+		}
+		for from, vm := range p.ValueMap {
+			if cp.ValueMap == nil {
+				cp.ValueMap = map[string]string{}
+			}
+			if vm.To != nil {
+				cp.ValueMap[from] = *vm.To
+			}
+		}
+		for name, t := range p.Tag {
+			if cp.Tags == nil {
+				cp.Tags = map[string]string{}
+			}
+			if t.Value != nil {
+				cp.Tags[name] = *t.Value
+			}
+		}
+		cps = append(cps, cp)
+	}
+	return cps
+}
+
+// canonicalDuration returns the duration in a canonical notation, e.g. 10s and
+// 10000ms are the same, invalid durations are returned as is
+func canonicalDuration(d *string) string {
+	if d == nil {
+		return ""
+	}
+	pd, err := time.ParseDuration(*d)
+	if err != nil {
+		return *d
+	}
+	return pd.String()
+}
+
+// targetFingerprint returns a hash of the fingerprints of all state entries of a target
+func targetFingerprint(mc *ygotnddpstate.Device) string {
+	names := make([]string, 0, len(mc.StateEntry))
+	for name := range mc.StateEntry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		fp := fingerprint(mc.StateEntry[name])
+		if fp == "" {
+			return ""
+		}
+		h.Write([]byte(name + "=" + fp + "\n"))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
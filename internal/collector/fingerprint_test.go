@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+func strPtr(s string) *string { return &s }
+
+func TestFingerprint(t *testing.T) {
+	base := &ygotnddpstate.YnddState_StateEntry{
+		Name:           strPtr("itfce"),
+		Path:           []string{"/interface[name=*]/oper-state", "/interface[name=*]/admin-state"},
+		SampleInterval: strPtr("10s"),
+	}
+	tests := []struct {
+		name string
+		se   *ygotnddpstate.YnddState_StateEntry
+		want bool
+	}{
+		{
+			name: "path order and duration notation",
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name:           strPtr("itfce"),
+				Path:           []string{"/interface[name=*]/admin-state", "/interface[name=*]/oper-state"},
+				SampleInterval: strPtr("10000ms"),
+				Mode:           ygotnddpstate.YnddState_StateEntry_Mode_on_change,
+			},
+			want: true,
+		},
+		{
+			name: "sample interval changed",
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name:           strPtr("itfce"),
+				Path:           []string{"/interface[name=*]/oper-state", "/interface[name=*]/admin-state"},
+				SampleInterval: strPtr("20s"),
+			},
+			want: false,
+		},
+		{
+			name: "path removed",
+			se: &ygotnddpstate.YnddState_StateEntry{
+				Name:           strPtr("itfce"),
+				Path:           []string{"/interface[name=*]/oper-state"},
+				SampleInterval: strPtr("10s"),
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fingerprint(tt.se) == fingerprint(base); got != tt.want {
+				t.Errorf("fingerprint() equal = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
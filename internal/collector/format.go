@@ -0,0 +1,74 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
+)
+
+// MessageFormat defines which information the published messages carry besides the subject
+type MessageFormat string
+
+const (
+	// MessageFormatSubject encodes the path and its keys only in the subject of the message
+	MessageFormatSubject MessageFormat = "subject"
+	// MessageFormatEvent adds the xpath without keys, the origin and every list key as
+	// tags to the message such that consumers do not have to parse the subject
+	MessageFormatEvent MessageFormat = "event"
+
+	// event message tags
+	tagPath   = "path"
+	tagOrigin = "origin"
+
+	// errors
+	errUnknownMessageFormat = "unknown message format"
+)
+
+// ValidateMessageFormat returns an error if the format is not a known message format
+func ValidateMessageFormat(f MessageFormat) error {
+	switch f {
+	case MessageFormatSubject, MessageFormatEvent:
+		return nil
+	}
+	return errors.Errorf("%s: %s", errUnknownMessageFormat, f)
+}
+
+// eventTags returns the tags of a message in event format for the absolute path p,
+// e.g. /interface[name=ethernet-1/1]/oper-state results in the tags
+// path=/interface/oper-state and interface_name=ethernet-1/1
+func eventTags(p *gnmi.Path) map[string]string {
+	tags := map[string]string{}
+	sb := new(strings.Builder)
+	for _, e := range p.GetElem() {
+		fmt.Fprintf(sb, "/%s", e.GetName())
+		for k, v := range e.GetKey() {
+			tags[fmt.Sprintf("%s_%s", e.GetName(), k)] = v
+		}
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("/")
+	}
+	tags[tagPath] = sb.String()
+	if p.GetOrigin() != "" {
+		tags[tagOrigin] = p.GetOrigin()
+	}
+	return tags
+}
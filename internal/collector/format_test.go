@@ -0,0 +1,42 @@
+package collector
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestEventTags(t *testing.T) {
+	cases := map[string]struct {
+		path *gnmi.Path
+		want map[string]string
+	}{
+		"Keys": {
+			path: &gnmi.Path{Elem: []*gnmi.PathElem{
+				{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}},
+				{Name: "subinterface", Key: map[string]string{"index": "0"}},
+				{Name: "oper-state"},
+			}},
+			want: map[string]string{
+				tagPath:              "/interface/subinterface/oper-state",
+				"interface_name":     "ethernet-1/1",
+				"subinterface_index": "0",
+			},
+		},
+		"Origin": {
+			path: &gnmi.Path{Origin: "openconfig", Elem: []*gnmi.PathElem{{Name: "system"}, {Name: "hostname"}}},
+			want: map[string]string{
+				tagPath:   "/system/hostname",
+				tagOrigin: "openconfig",
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			if got := eventTags(tc.path); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("eventTags() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
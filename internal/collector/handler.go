@@ -3,74 +3,223 @@ package collector
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-yang/pkg/yparser"
 	"github.com/yndd/pubsub"
 	statesubject "github.com/yndd/state/pkg/subject"
+	"github.com/yndd/state/pkg/value"
 )
 
-func (c *targetCollector) handleSubscribeResponse(resp *gnmi.SubscribeResponse) error {
+func (c *targetCollector) handleSubscribeResponse(s *Subscription, resp *gnmi.SubscribeResponse) error {
 	targetName := c.GetTarget().Config.Name
 
-	log := c.log.WithValues("Target", targetName)
+	log := c.log.WithValues("Target", targetName, "Subscription", s.GetName())
 	//log.Debug("handle target update from device")
 
+	if s.setConnected() {
+		if err := c.publishConnectivity(s, ConnectivityConnected, nil); err != nil {
+			return err
+		}
+		// a stream that receives responses after the connection to the target was lost
+		// shows the target is connected again
+		if err := c.publishTargetConnectivity(ConnectivityConnected, nil); err != nil {
+			return err
+		}
+	}
+
 	switch resp.GetResponse().(type) {
 	case *gnmi.SubscribeResponse_Update:
 		log.Debug("handle target update from device", "Prefix", resp.GetUpdate().GetPrefix())
-
-		for _, msg := range c.notificationToPubSubMsg(targetName, resp.GetUpdate()) {
-			c.updateCh <- msg
+		notificationsReceived.WithLabelValues(targetName, s.GetName()).Inc()
+		lastUpdates.set(targetName, s.GetName())
+		s.setUpdated()
+		if err := c.handleNotification(s, resp.GetUpdate()); err != nil {
+			return err
 		}
 
 	case *gnmi.SubscribeResponse_SyncResponse:
 		log.Debug("SyncResponse")
+		if first, resynced, startTime := s.setSynced(); first {
+			if resynced {
+				if err := c.resync(s); err != nil {
+					return err
+				}
+			}
+			// a synced subscription resets its own backoff and shows that the target
+			// is reachable
+			s.retry.success()
+			c.retry.success()
+			syncLatency.WithLabelValues(targetName, s.GetName()).Observe(time.Since(startTime).Seconds())
+			if err := c.resyncTarget(); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
 }
 
-// TODO: sanitize subject before returning
-func (c *targetCollector) notificationToPubSubMsg(targetName string, n *gnmi.Notification) []*pubsub.Msg {
+// resync completes the sync of a subscription after a disconnect, the paths that did
+// not reappear are deleted when configured for the state entry
+func (c *targetCollector) resync(s *Subscription) error {
+	if s.paths != nil && s.deleteAfterResync() {
+		if deletes := s.paths.unseen(); len(deletes) > 0 {
+			if err := c.handleSyntheticDeletes(s, deletes); err != nil {
+				return err
+			}
+		}
+	}
+	return c.publishConnectivity(s, ConnectivityResynced, nil)
+}
+
+// handleNotification updates the caches and the exporter with a notification of a
+// subscription and publishes it
+func (c *targetCollector) handleNotification(s *Subscription, n *gnmi.Notification) error {
+	targetName := c.GetTarget().Config.Name
+	if s.paths != nil {
+		s.paths.update(n, time.Now())
+	}
+
+	// the processors of the state entry apply to everything that is published
+	n, tags := s.processors.apply(n)
+	if c.lastValueCache != nil {
+		c.lastValueCache.Update(targetName, n)
+	}
+	if c.exporter != nil {
+		c.exporter.Update(targetName, s.StateEntry, n)
+	}
+
+	for _, msg := range c.notificationToPubSubMsg(targetName, s, n, tags) {
+		if err := c.queue.push(c.ctx, msg); err != nil {
+			// the target collector is stopped
+			return err
+		}
+	}
+	return nil
+}
+
+// handleSyntheticDeletes publishes the deletes of paths the target did not report again,
+// they are not received from the target and are counted separately from the notifications
+func (c *targetCollector) handleSyntheticDeletes(s *Subscription, deletes []*gnmi.Path) error {
+	syntheticDeletes.WithLabelValues(c.GetTarget().Config.Name, s.GetName()).Add(float64(len(deletes)))
+	return c.handleNotification(s, &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Delete:    deletes,
+	})
+}
+
+// subjectPrefix returns the subject prefix of the messages of a state entry, it is
+// composed of the stream name, the target, the prefix and the name of the state entry
+func subjectPrefix(targetName string, s *Subscription) string {
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "%s.%s", streamName, statesubject.SanitizeToken(targetName))
+	if s.StateEntry.Prefix != nil && *s.StateEntry.Prefix != "" {
+		fmt.Fprintf(sb, ".%s", statesubject.SanitizeToken(*s.StateEntry.Prefix))
+	}
+	fmt.Fprintf(sb, ".%s", statesubject.SanitizeToken(s.GetName()))
+	return sb.String()
+}
+
+// notificationToPubSubMsg converts a notification into pubsub messages, the subject of a message is
+// composed of the stream name, the target, the prefix and the name of the state entry and the path
+// of the update, e.g. nddpstate.leaf1.itfce.interface.interface.{name=ethernet-1^1}.oper-state.
+// The processor tags pt are the tags the processors of the state entry added to the values.
+func (c *targetCollector) notificationToPubSubMsg(targetName string, s *Subscription, n *gnmi.Notification, pt *valueTags) []*pubsub.Msg {
 	sb := new(strings.Builder)
-	fmt.Fprintf(sb, "%s.%s", streamName, targetName)
+	sb.WriteString(subjectPrefix(targetName, s))
 	if pr := statesubject.GNMIPathToSubject(n.GetPrefix()); pr != "" {
 		fmt.Fprintf(sb, ".%s", pr)
 	}
 	prefix := sb.String()
+	tags := stateEntryTags(targetName, s)
 	result := make([]*pubsub.Msg, 0, len(n.GetUpdate())+len(n.GetDelete()))
-	for _, upd := range n.GetUpdate() {
+	for i, upd := range n.GetUpdate() {
 		if pr := statesubject.GNMIPathToSubject(upd.GetPath()); pr != "" {
+			b, vt, err := value.ToBytes(upd.GetVal())
+			if err != nil {
+				c.log.Debug("cannot convert value", "path", pr, "error", err)
+				continue
+			}
+			p := absolutePath(n.GetPrefix(), upd.GetPath())
+			var transition map[string]string
+			if s.changes != nil {
+				cur := trackedValue{data: b, valueType: vt, timestamp: n.GetTimestamp()}
+				prev, hasPrev, changed := s.changes.update(yparser.GnmiPath2XPath(p, true), cur)
+				if !changed {
+					continue
+				}
+				transition = transitionTags(prev, hasPrev, cur)
+			}
 			sb.Reset()
 			fmt.Fprintf(sb, "%s.%s", prefix, pr)
 			sm := &pubsub.Msg{
 				Subject:   sb.String(),
 				Timestamp: n.GetTimestamp(),
 				Operation: pubsub.Operation_OPERATION_UPDATE,
-				Data:      []byte(upd.Val.GetStringVal()),
-				Tags: map[string]string{
-					"target": targetName,
-				},
+				Data:      b,
+				Tags:      copyTags(tags),
 			}
+			c.addMessageTags(sm, p, pt.updateTags(i))
+			for k, tv := range transition {
+				sm.Tags[k] = tv
+			}
+			sm.Tags[tagValueType] = vt
 			c.log.Debug("state message", "notification", n, "msg", sm)
 			result = append(result, sm)
 		}
 	}
-	for _, del := range n.GetDelete() {
+	for i, del := range n.GetDelete() {
 		if pr := statesubject.GNMIPathToSubject(del); pr != "" {
+			if s.changes != nil {
+				s.changes.delete(yparser.GnmiPath2XPath(absolutePath(n.GetPrefix(), del), true))
+			}
 			sb.Reset()
 			fmt.Fprintf(sb, "%s.%s", prefix, pr)
 			sm := &pubsub.Msg{
 				Subject:   sb.String(),
 				Timestamp: n.GetTimestamp(),
 				Operation: pubsub.Operation_OPERATION_DELETE,
-				Tags: map[string]string{
-					"target": targetName,
-				},
+				Tags:      copyTags(tags),
 			}
+			c.addMessageTags(sm, absolutePath(n.GetPrefix(), del), pt.deleteTags(i))
 			c.log.Debug("state message", "notification", n, "msg", sm)
 			result = append(result, sm)
 		}
 	}
 	return result
 }
+
+// addMessageTags adds the tags the processors of the state entry added to the value and,
+// in event format, the tags derived from the absolute path p of the value to a message
+func (c *targetCollector) addMessageTags(sm *pubsub.Msg, p *gnmi.Path, tags map[string]string) {
+	if c.messageFormat == MessageFormatEvent {
+		for k, tv := range eventTags(p) {
+			sm.Tags[k] = tv
+		}
+	}
+	for k, tv := range tags {
+		sm.Tags[k] = tv
+	}
+}
+
+// stateEntryTags returns the tags every message of a state entry carries
+func stateEntryTags(targetName string, s *Subscription) map[string]string {
+	tags := map[string]string{
+		tagTarget:     targetName,
+		tagStateEntry: s.GetName(),
+	}
+	if s.StateEntry.Prefix != nil && *s.StateEntry.Prefix != "" {
+		tags[tagPrefix] = *s.StateEntry.Prefix
+	}
+	return tags
+}
+
+func copyTags(tags map[string]string) map[string]string {
+	c := make(map[string]string, len(tags))
+	for k, v := range tags {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,202 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	metricsNamespace = "ndd"
+	metricsSubsystem = "state_collector"
+	// metric labels
+	labelTarget       = "target"
+	labelSubscription = "subscription"
+	labelPolicy       = "policy"
+	labelOutput       = "output"
+)
+
+var (
+	droppedMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "dropped_messages_total",
+		Help:      "Number of messages dropped because the target queue was full.",
+	}, []string{labelTarget, labelPolicy})
+
+	queueLength = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "queue_length",
+		Help:      "Number of messages waiting in the target queue to be published.",
+	}, []string{labelTarget})
+
+	notificationsReceived = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "notifications_received_total",
+		Help:      "Number of notifications received from the target per subscription.",
+	}, []string{labelTarget, labelSubscription})
+
+	syntheticDeletes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "synthetic_deletes_total",
+		Help:      "Number of deletes generated for paths the target no longer reports per subscription.",
+	}, []string{labelTarget, labelSubscription})
+
+	msgsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "published_messages_total",
+		Help:      "Number of messages written to the outputs.",
+	}, []string{labelTarget})
+
+	publishFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "publish_failures_total",
+		Help:      "Number of messages that could not be written to an output.",
+	}, []string{labelTarget})
+
+	outputDroppedMsgs = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "output_dropped_messages_total",
+		Help:      "Number of messages dropped by an output because its queue was full or the write failed.",
+	}, []string{labelOutput})
+
+	reconnects = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "reconnects_total",
+		Help:      "Number of times a subscription failed and is reconnected.",
+	}, []string{labelTarget, labelSubscription})
+
+	dialFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "dial_failures_total",
+		Help:      "Number of times the gnmi client of a target could not be created.",
+	}, []string{labelTarget})
+
+	syncLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: metricsSubsystem,
+		Name:      "sync_latency_seconds",
+		Help:      "Time between starting a subscription and receiving its sync response.",
+		Buckets:   prometheus.ExponentialBuckets(0.1, 2, 10),
+	}, []string{labelTarget, labelSubscription})
+
+	lastUpdates = newLastUpdateCollector()
+)
+
+// lastUpdateCollector exposes the time since the last update per subscription,
+// the time is computed when the metrics are collected
+type lastUpdateCollector struct {
+	m    sync.RWMutex
+	desc *prometheus.Desc
+	// last update times indexed by target and subscription
+	times map[string]map[string]time.Time
+}
+
+func newLastUpdateCollector() *lastUpdateCollector {
+	return &lastUpdateCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(metricsNamespace, metricsSubsystem, "seconds_since_last_update"),
+			"Time since the last notification was received from the target per subscription.",
+			[]string{labelTarget, labelSubscription}, nil),
+		times: map[string]map[string]time.Time{},
+	}
+}
+
+func (c *lastUpdateCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+func (c *lastUpdateCollector) Collect(ch chan<- prometheus.Metric) {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	now := time.Now()
+	for target, subs := range c.times {
+		for sub, t := range subs {
+			ch <- prometheus.MustNewConstMetric(c.desc, prometheus.GaugeValue, now.Sub(t).Seconds(), target, sub)
+		}
+	}
+}
+
+func (c *lastUpdateCollector) set(target, sub string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if _, ok := c.times[target]; !ok {
+		c.times[target] = map[string]time.Time{}
+	}
+	c.times[target][sub] = time.Now()
+}
+
+func (c *lastUpdateCollector) delete(target, sub string) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	delete(c.times[target], sub)
+	if len(c.times[target]) == 0 {
+		delete(c.times, target)
+	}
+}
+
+// deleteSubscriptionMetrics removes the metrics of a subscription
+func deleteSubscriptionMetrics(target, sub string) {
+	notificationsReceived.DeleteLabelValues(target, sub)
+	syntheticDeletes.DeleteLabelValues(target, sub)
+	reconnects.DeleteLabelValues(target, sub)
+	syncLatency.DeleteLabelValues(target, sub)
+	lastUpdates.delete(target, sub)
+}
+
+// deleteTargetMetrics removes the per target metrics and the metrics of the subscriptions
+// of the target
+func deleteTargetMetrics(target string, subs []string) {
+	for _, sub := range subs {
+		deleteSubscriptionMetrics(target, sub)
+	}
+	msgsPublished.DeleteLabelValues(target)
+	publishFailures.DeleteLabelValues(target)
+	dialFailures.DeleteLabelValues(target)
+	for _, policy := range []OverflowPolicy{OverflowPolicyBlock, OverflowPolicyDropOldest, OverflowPolicyDropNewest} {
+		droppedMsgs.DeleteLabelValues(target, string(policy))
+	}
+}
+
+func init() {
+	// the metrics are served by the controller-runtime metrics endpoint
+	metrics.Registry.MustRegister(
+		droppedMsgs,
+		queueLength,
+		notificationsReceived,
+		syntheticDeletes,
+		msgsPublished,
+		publishFailures,
+		outputDroppedMsgs,
+		reconnects,
+		dialFailures,
+		syncLatency,
+		lastUpdates,
+	)
+}
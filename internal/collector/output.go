@@ -0,0 +1,47 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"time"
+
+	"github.com/yndd/pubsub"
+)
+
+const (
+	// output kinds
+	OutputKindNATS   = "nats"
+	OutputKindStdout = "stdout"
+	OutputKindFile   = "file"
+	OutputKindMemory = "memory"
+
+	// defaultOutputCloseTimeout is the time an output gets to write the pending messages when it is closed
+	defaultOutputCloseTimeout = 5 * time.Second
+
+	// errors
+	errOutputClosed = "output is closed"
+	errOutputFull   = "output queue is full"
+)
+
+// Output defines the interface of a destination the collected state is published to
+type Output interface {
+	// Write publishes a message to the output
+	Write(ctx context.Context, msg *pubsub.Msg) error
+	// Close releases the resources of the output
+	Close() error
+}
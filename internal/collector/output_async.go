@@ -0,0 +1,119 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/pubsub"
+)
+
+const (
+	defaultAsyncOutputSize = 1000
+)
+
+// asyncOutput writes the messages to an output from its own goroutine such that
+// a slow or unavailable output does not hold up the other outputs
+type asyncOutput struct {
+	name string
+	o    Output
+	log  logging.Logger
+
+	m      sync.RWMutex
+	closed bool
+	ch     chan *pubsub.Msg
+
+	closeTimeout time.Duration
+	cfn          context.CancelFunc
+	done         chan struct{}
+}
+
+// NewAsyncOutput creates an output that queues up to size messages for o, the messages
+// written while the queue is full are dropped and counted per output name.
+func NewAsyncOutput(name string, o Output, size int, log logging.Logger) Output {
+	if size <= 0 {
+		size = defaultAsyncOutputSize
+	}
+	a := &asyncOutput{
+		name:         name,
+		o:            o,
+		log:          log,
+		ch:           make(chan *pubsub.Msg, size),
+		closeTimeout: defaultOutputCloseTimeout,
+		done:         make(chan struct{}),
+	}
+	var ctx context.Context
+	ctx, a.cfn = context.WithCancel(context.Background())
+	go a.run(ctx)
+	return a
+}
+
+func (a *asyncOutput) Write(ctx context.Context, msg *pubsub.Msg) error {
+	a.m.RLock()
+	defer a.m.RUnlock()
+	if a.closed {
+		return errors.New(errOutputClosed)
+	}
+	select {
+	case a.ch <- msg:
+		return nil
+	default:
+		outputDroppedMsgs.WithLabelValues(a.name).Inc()
+		return errors.New(errOutputFull)
+	}
+}
+
+// Close stops accepting messages and waits for the queued messages to be written,
+// the messages that are not written within the close timeout are dropped.
+func (a *asyncOutput) Close() error {
+	a.m.Lock()
+	if a.closed {
+		a.m.Unlock()
+		return nil
+	}
+	a.closed = true
+	close(a.ch)
+	a.m.Unlock()
+
+	select {
+	case <-a.done:
+	case <-time.After(a.closeTimeout):
+		a.log.Debug("output close timeout, dropping queued messages", "output", a.name, "queued", len(a.ch))
+		a.cfn()
+		<-a.done
+	}
+	a.cfn()
+	return a.o.Close()
+}
+
+func (a *asyncOutput) run(ctx context.Context) {
+	defer close(a.done)
+	for msg := range a.ch {
+		if ctx.Err() != nil {
+			outputDroppedMsgs.WithLabelValues(a.name).Inc()
+			continue
+		}
+		if err := a.o.Write(ctx, msg); err != nil {
+			a.log.Debug("output write failed", "output", a.name, "subject", msg.GetSubject(), "error", err)
+			outputDroppedMsgs.WithLabelValues(a.name).Inc()
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/pubsub"
+)
+
+// blockingOutput blocks the writes until released, like a nats output without connection
+type blockingOutput struct {
+	*MemoryOutput
+	writing chan struct{}
+	release chan struct{}
+	closed  bool
+}
+
+func newBlockingOutput() *blockingOutput {
+	return &blockingOutput{
+		MemoryOutput: NewMemoryOutput(0),
+		writing:      make(chan struct{}, 10),
+		release:      make(chan struct{}),
+	}
+}
+
+func (o *blockingOutput) Write(ctx context.Context, msg *pubsub.Msg) error {
+	o.writing <- struct{}{}
+	select {
+	case <-o.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return o.MemoryOutput.Write(ctx, msg)
+}
+
+func (o *blockingOutput) Close() error {
+	o.closed = true
+	return nil
+}
+
+func TestAsyncOutputBlockedOutput(t *testing.T) {
+	const name = "test/blocked"
+	defer outputDroppedMsgs.DeleteLabelValues(name)
+	blocked := newBlockingOutput()
+	mem := NewMemoryOutput(0)
+	outs := []Output{
+		NewAsyncOutput(name, blocked, 1, logging.NewNopLogger()),
+		NewAsyncOutput("test/memory", mem, 10, logging.NewNopLogger()),
+	}
+	write := func(subject string) []error {
+		errs := make([]error, 0, len(outs))
+		for _, o := range outs {
+			errs = append(errs, o.Write(context.Background(), &pubsub.Msg{Subject: subject}))
+		}
+		return errs
+	}
+
+	// the first msg is being written, the second is queued and the third is dropped by
+	// the blocked output only
+	write("a")
+	<-blocked.writing
+	write("b")
+	if errs := write("c"); errs[0] == nil || errs[1] != nil {
+		t.Errorf("Write() with a full queue = %v, want an error of the blocked output only", errs)
+	}
+	if dropped := testutil.ToFloat64(outputDroppedMsgs.WithLabelValues(name)); dropped != 1 {
+		t.Errorf("dropped = %v, want 1", dropped)
+	}
+
+	// close waits for the queued messages to be written
+	close(blocked.release)
+	for _, o := range outs {
+		if err := o.Close(); err != nil {
+			t.Errorf("Close() error = %v", err)
+		}
+	}
+	if got := len(blocked.Messages()); got != 2 || !blocked.closed {
+		t.Errorf("blocked output after close: %d messages, closed %v, want 2 messages and closed", got, blocked.closed)
+	}
+	if got := len(mem.Messages()); got != 3 {
+		t.Errorf("memory output after close: %d messages, want 3", got)
+	}
+	if err := outs[0].Write(context.Background(), &pubsub.Msg{}); err == nil {
+		t.Errorf("Write() after Close(): want error")
+	}
+}
+
+func TestAsyncOutputCloseTimeout(t *testing.T) {
+	const name = "test/close-timeout"
+	defer outputDroppedMsgs.DeleteLabelValues(name)
+	blocked := newBlockingOutput()
+	o := NewAsyncOutput(name, blocked, 2, logging.NewNopLogger()).(*asyncOutput)
+	o.closeTimeout = 10 * time.Millisecond
+
+	o.Write(context.Background(), &pubsub.Msg{})
+	<-blocked.writing
+	o.Write(context.Background(), &pubsub.Msg{})
+
+	// the output never recovers, the in flight and the queued msg are dropped
+	done := make(chan struct{})
+	go func() {
+		o.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close() did not return after the close timeout")
+	}
+	if dropped := testutil.ToFloat64(outputDroppedMsgs.WithLabelValues(name)); dropped != 2 {
+		t.Errorf("dropped = %v, want 2", dropped)
+	}
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/yndd/pubsub"
+)
+
+const (
+	errOpenOutputFile = "cannot open output file"
+)
+
+// fileMsg is the json representation of a message written by the file output
+type fileMsg struct {
+	Subject   string            `json:"subject"`
+	Timestamp int64             `json:"timestamp"`
+	Operation string            `json:"operation"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Data      string            `json:"data,omitempty"`
+}
+
+// fileOutput writes the messages as json lines
+type fileOutput struct {
+	m sync.Mutex
+	w io.Writer
+	// closer is nil when the writer is not owned by the output, e.g. stdout
+	closer io.Closer
+}
+
+// NewStdoutOutput creates an output that writes the messages as json lines to stdout
+func NewStdoutOutput() Output {
+	return NewWriterOutput(os.Stdout)
+}
+
+// NewWriterOutput creates an output that writes the messages as json lines to w
+func NewWriterOutput(w io.Writer) Output {
+	return &fileOutput{w: w}
+}
+
+// NewFileOutput creates an output that appends the messages as json lines to the file
+func NewFileOutput(path string) (Output, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, errors.Wrap(err, errOpenOutputFile)
+	}
+	return &fileOutput{w: f, closer: f}, nil
+}
+
+func (o *fileOutput) Write(ctx context.Context, msg *pubsub.Msg) error {
+	b, err := json.Marshal(&fileMsg{
+		Subject:   msg.GetSubject(),
+		Timestamp: msg.GetTimestamp(),
+		Operation: msg.GetOperation().String(),
+		Tags:      msg.GetTags(),
+		Data:      string(msg.GetData()),
+	})
+	if err != nil {
+		return err
+	}
+	o.m.Lock()
+	defer o.m.Unlock()
+	_, err = o.w.Write(append(b, '\n'))
+	return err
+}
+
+func (o *fileOutput) Close() error {
+	if o.closer == nil {
+		return nil
+	}
+	return o.closer.Close()
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yndd/pubsub"
+)
+
+const (
+	defaultMemoryOutputSize = 1000
+)
+
+// MemoryOutput keeps the last published messages in memory
+type MemoryOutput struct {
+	m    sync.RWMutex
+	size int
+	msgs []*pubsub.Msg
+}
+
+// NewMemoryOutput creates an output that keeps the last size messages in memory,
+// older messages are discarded.
+func NewMemoryOutput(size int) *MemoryOutput {
+	if size <= 0 {
+		size = defaultMemoryOutputSize
+	}
+	return &MemoryOutput{
+		size: size,
+		msgs: make([]*pubsub.Msg, 0, size),
+	}
+}
+
+func (o *MemoryOutput) Write(ctx context.Context, msg *pubsub.Msg) error {
+	o.m.Lock()
+	defer o.m.Unlock()
+	if len(o.msgs) == o.size {
+		copy(o.msgs, o.msgs[1:])
+		o.msgs = o.msgs[:o.size-1]
+	}
+	o.msgs = append(o.msgs, msg)
+	return nil
+}
+
+func (o *MemoryOutput) Close() error {
+	return nil
+}
+
+// Messages returns the messages kept by the output, oldest first
+func (o *MemoryOutput) Messages() []*pubsub.Msg {
+	o.m.RLock()
+	defer o.m.RUnlock()
+	msgs := make([]*pubsub.Msg, len(o.msgs))
+	copy(msgs, o.msgs)
+	return msgs
+}
+
+// Reset discards the messages kept by the output
+func (o *MemoryOutput) Reset() {
+	o.m.Lock()
+	defer o.m.Unlock()
+	o.msgs = o.msgs[:0]
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"crypto/tls"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/pubsub"
+)
+
+const (
+	// errors
+	errNATSConnect        = "cannot connect to nats"
+	errNATSJetStream      = "cannot create nats jetstream context"
+	errNATSStream         = "cannot create nats stream"
+	errNATSNKey           = "cannot parse nats nkey seed"
+	errNATSCredentials    = "cannot parse nats credentials"
+	errNATSMarshalMsg     = "cannot marshal msg"
+	errNATSPublish        = "cannot publish msg"
+	defaultNATSClientName = "ndd-state-collector"
+)
+
+// NATSConfig holds the connection parameters of the NATS output
+type NATSConfig struct {
+	// Address is a comma separated list of nats server addresses
+	Address string
+	// TLS is used to connect to the servers, when nil the connection is not encrypted
+	TLS *tls.Config
+	// Username and Password authenticate the connection with user/password
+	Username string
+	Password string
+	// NKeySeed authenticates the connection with an nkey
+	NKeySeed []byte
+	// Credentials holds the content of a nats creds file (user jwt and nkey seed)
+	Credentials []byte
+}
+
+// Servers returns the server addresses of the address list with
+// surrounding whitespace and empty entries removed
+func (c *NATSConfig) Servers() []string {
+	servers := make([]string, 0)
+	for _, s := range strings.Split(c.Address, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			servers = append(servers, s)
+		}
+	}
+	return servers
+}
+
+func (c *NATSConfig) options() ([]nats.Option, error) {
+	opts := []nats.Option{
+		nats.Name(defaultNATSClientName),
+		nats.MaxReconnects(-1),
+		nats.ReconnectWait(defaultRetryTimer),
+	}
+	if c.TLS != nil {
+		opts = append(opts, nats.Secure(c.TLS))
+	}
+	if c.Username != "" {
+		opts = append(opts, nats.UserInfo(c.Username, c.Password))
+	}
+	if len(c.NKeySeed) > 0 {
+		kp, err := nkeys.FromSeed(c.NKeySeed)
+		if err != nil {
+			return nil, errors.Wrap(err, errNATSNKey)
+		}
+		pub, err := kp.PublicKey()
+		if err != nil {
+			return nil, errors.Wrap(err, errNATSNKey)
+		}
+		opts = append(opts, nats.Nkey(pub, kp.Sign))
+	}
+	if len(c.Credentials) > 0 {
+		jwt, err := nkeys.ParseDecoratedJWT(c.Credentials)
+		if err != nil {
+			return nil, errors.Wrap(err, errNATSCredentials)
+		}
+		kp, err := nkeys.ParseDecoratedNKey(c.Credentials)
+		if err != nil {
+			return nil, errors.Wrap(err, errNATSCredentials)
+		}
+		opts = append(opts, nats.UserJWT(
+			func() (string, error) { return jwt, nil },
+			kp.Sign,
+		))
+	}
+	return opts, nil
+}
+
+// natsOutput publishes the messages to a NATS jetstream
+type natsOutput struct {
+	cfg *NATSConfig
+	ch  chan *pubsub.Msg
+	// pending holds the msg that failed to publish, it is retried after reconnecting
+	pending *pubsub.Msg
+	// stopCh is closed when the output is closed, done when the publisher stopped
+	stopCh   chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+	cfn      context.CancelFunc
+	log      logging.Logger
+}
+
+// NewNATSOutput creates an output that publishes the messages to the NATS server(s) in the config,
+// the output reconnects until it is closed.
+func NewNATSOutput(ctx context.Context, cfg *NATSConfig, log logging.Logger) (Output, error) {
+	if len(cfg.Servers()) == 0 {
+		return nil, errors.Wrap(errors.New("no server address"), errNATSConnect)
+	}
+	// validate the credentials upfront such that a wrong secret is reported at startup
+	if _, err := cfg.options(); err != nil {
+		return nil, err
+	}
+	o := &natsOutput{
+		cfg:    cfg,
+		ch:     make(chan *pubsub.Msg),
+		stopCh: make(chan struct{}),
+		done:   make(chan struct{}),
+		log:    log,
+	}
+	ctx, o.cfn = context.WithCancel(ctx)
+	go o.run(ctx)
+	return o, nil
+}
+
+func (o *natsOutput) Write(ctx context.Context, msg *pubsub.Msg) error {
+	select {
+	case o.ch <- msg:
+		return nil
+	case <-o.stopCh:
+		return errors.New(errOutputClosed)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting messages and waits for the publisher to publish the pending
+// msg, the msg is dropped when it is not published within the close timeout.
+func (o *natsOutput) Close() error {
+	o.stopOnce.Do(func() { close(o.stopCh) })
+	select {
+	case <-o.done:
+	case <-time.After(defaultOutputCloseTimeout):
+		o.log.Debug("nats publisher close timeout, dropping pending msg", "servers", o.cfg.Servers())
+	}
+	o.cfn()
+	<-o.done
+	return nil
+}
+
+func (o *natsOutput) stopped() bool {
+	select {
+	case <-o.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+func (o *natsOutput) run(ctx context.Context) {
+	defer close(o.done)
+	for {
+		if err := o.publish(ctx); err != nil {
+			o.log.Debug("nats publisher failed", "servers", o.cfg.Servers(), "error", err)
+		}
+		if o.stopped() && o.pending == nil {
+			o.log.Debug("nats publisher stopped")
+			return
+		}
+		select {
+		case <-ctx.Done():
+			o.log.Debug("nats publisher stopped", "error", ctx.Err())
+			return
+		case <-time.After(defaultRetryTimer):
+		}
+	}
+}
+
+// publish connects to the nats servers and publishes the messages
+// until the context is done or a publish fails
+func (o *natsOutput) publish(ctx context.Context) error {
+	opts, err := o.cfg.options()
+	if err != nil {
+		return err
+	}
+	nc, err := nats.Connect(strings.Join(o.cfg.Servers(), ","), opts...)
+	if err != nil {
+		return errors.Wrap(err, errNATSConnect)
+	}
+	defer nc.Close()
+	o.log.Debug("nats publisher connected", "server", nc.ConnectedUrl())
+
+	js, err := nc.JetStream()
+	if err != nil {
+		return errors.Wrap(err, errNATSJetStream)
+	}
+	if _, err := js.StreamInfo(streamName); err != nil {
+		if !errors.Is(err, nats.ErrStreamNotFound) {
+			return errors.Wrap(err, errNATSStream)
+		}
+		if _, err := js.AddStream(&nats.StreamConfig{
+			Name:     streamName,
+			Subjects: []string{streamSubjects},
+		}); err != nil {
+			return errors.Wrap(err, errNATSStream)
+		}
+	}
+
+	for {
+		if o.pending == nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-o.stopCh:
+				return nil
+			case o.pending = <-o.ch:
+			}
+		}
+		b, err := proto.Marshal(o.pending)
+		if err != nil {
+			// a message that cannot be marshaled is dropped, it will never succeed
+			o.log.Debug(errNATSMarshalMsg, "subject", o.pending.GetSubject(), "error", err)
+			o.pending = nil
+			continue
+		}
+		if _, err := js.Publish(o.pending.GetSubject(), b); err != nil {
+			return errors.Wrap(err, errNATSPublish)
+		}
+		o.pending = nil
+	}
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"time"
+
+	gapi "github.com/karimra/gnmic/api"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+const (
+	// interval between the gets of a state entry in poll mode without sample interval
+	defaultPollInterval = 10 * time.Second
+
+	// errors
+	errCreateGetRequest = "cannot create get request"
+)
+
+// isPoll returns true if the state is collected with periodic gets instead of a subscription
+func (s *Subscription) isPoll() bool {
+	return s.StateEntry.Mode == ygotnddpstate.YnddState_StateEntry_Mode_poll
+}
+
+// pollInterval returns the interval between the gets of the state entry
+func (s *Subscription) pollInterval() (time.Duration, error) {
+	if s.StateEntry.SampleInterval == nil {
+		return defaultPollInterval, nil
+	}
+	d, err := time.ParseDuration(*s.StateEntry.SampleInterval)
+	if err != nil {
+		return 0, errors.Wrap(err, errInvalidSampleInterval)
+	}
+	if d <= 0 {
+		return defaultPollInterval, nil
+	}
+	return d, nil
+}
+
+// createGetRequest creates the gnmi get request of a state entry in poll mode, only
+// the state data is requested
+func (s *Subscription) createGetRequest() (*gnmi.GetRequest, error) {
+	gnmiOpts := []gapi.GNMIOption{
+		gapi.Encoding(s.getSubscribeEncoding()),
+		gapi.DataTypeSTATE(),
+	}
+	for _, p := range s.StateEntry.Path {
+		gnmiOpts = append(gnmiOpts, gapi.Path(p))
+	}
+	return gapi.NewGetRequest(gnmiOpts...)
+}
+
+// poll gets the paths of a state entry on every poll interval until the context is
+// canceled. The responses are handled like subscribe responses, the first response
+// completes the sync and paths that disappeared since the previous get are deleted.
+func (c *targetCollector) poll(ctx context.Context, s *Subscription, id string, req *gnmi.GetRequest, interval time.Duration) {
+	log := c.log.WithValues("Target", c.target.Config.Name, "subscription", s.GetName())
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// leaf paths of the previous get, indexed by xpath
+	var previous map[string]*gnmi.Path
+	for {
+		resp, err := c.target.Get(ctx, req)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Debug("poll", "error", err)
+			// the poller is stopped and restarted with backoff like a failed subscription
+			c.handleSubscriptionError(id, err)
+			return
+		}
+		if c.getSubscriptionByID(id) == nil {
+			return
+		}
+		current, deletes := pollDiff(previous, resp.GetNotification())
+		previous = current
+
+		for _, n := range resp.GetNotification() {
+			if err := c.handleSubscribeResponse(s, &gnmi.SubscribeResponse{
+				Response: &gnmi.SubscribeResponse_Update{Update: n},
+			}); err != nil {
+				return
+			}
+		}
+		if len(deletes) > 0 {
+			if err := c.handleSyntheticDeletes(s, deletes); err != nil {
+				return
+			}
+		}
+		// the first get completes the sync, subsequent ones are ignored
+		c.handleSubscribeResponse(s, &gnmi.SubscribeResponse{
+			Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollDiff returns the leaf paths of the notifications of a get, indexed by xpath, and
+// the paths of the previous get that are no longer present
+func pollDiff(previous map[string]*gnmi.Path, ns []*gnmi.Notification) (map[string]*gnmi.Path, []*gnmi.Path) {
+	current := map[string]*gnmi.Path{}
+	for _, n := range ns {
+		for _, u := range n.GetUpdate() {
+			p := &gnmi.Path{
+				Origin: n.GetPrefix().GetOrigin(),
+				Elem:   append(append([]*gnmi.PathElem{}, n.GetPrefix().GetElem()...), u.GetPath().GetElem()...),
+			}
+			current[yparser.GnmiPath2XPath(p, true)] = p
+		}
+	}
+	deletes := []*gnmi.Path{}
+	for xpath, p := range previous {
+		if _, ok := current[xpath]; !ok {
+			deletes = append(deletes, p)
+		}
+	}
+	return current, deletes
+}
@@ -0,0 +1,89 @@
+package collector
+
+import (
+	"context"
+	"testing"
+
+	"github.com/karimra/gnmic/target"
+	"github.com/karimra/gnmic/types"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+func TestCreateGetRequest(t *testing.T) {
+	mc := &ygotnddpstate.Device{}
+	se, _ := mc.NewStateEntry("interface")
+	se.Path = []string{"/interface"}
+
+	req, err := NewSubscription(se).createGetRequest()
+	if err != nil {
+		t.Fatalf("createGetRequest() error = %v", err)
+	}
+	if req.GetType() != gnmi.GetRequest_STATE {
+		t.Errorf("createGetRequest() type = %s, want %s", req.GetType(), gnmi.GetRequest_STATE)
+	}
+	if len(req.GetPath()) != 1 {
+		t.Errorf("createGetRequest() got %d paths, want 1", len(req.GetPath()))
+	}
+}
+
+func TestSyntheticDeletesCounted(t *testing.T) {
+	tc := &types.TargetConfig{Name: "default/leaf-synthetic"}
+	c := &targetCollector{
+		target: target.NewTarget(tc),
+		queue:  newMsgQueue(tc.Name, 10, OverflowPolicyDropOldest),
+		log:    logging.NewNopLogger(),
+		ctx:    context.Background(),
+	}
+	defer deleteTargetMetrics(tc.Name, []string{"interface"})
+	defer c.queue.delete()
+
+	mc := &ygotnddpstate.Device{}
+	se, _ := mc.NewStateEntry("interface")
+	se.Path = []string{"/interface"}
+	s := NewSubscription(se)
+
+	deletes := []*gnmi.Path{
+		{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "e1"}}}},
+		{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "e2"}}}},
+	}
+	if err := c.handleSyntheticDeletes(s, deletes); err != nil {
+		t.Fatalf("handleSyntheticDeletes() error = %v", err)
+	}
+	if got := testutil.ToFloat64(syntheticDeletes.WithLabelValues(tc.Name, s.GetName())); got != 2 {
+		t.Errorf("synthetic deletes = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(notificationsReceived.WithLabelValues(tc.Name, s.GetName())); got != 0 {
+		t.Errorf("notifications received = %v, want 0", got)
+	}
+}
+
+func TestPollDiff(t *testing.T) {
+	notification := func(names ...string) []*gnmi.Notification {
+		n := &gnmi.Notification{
+			Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface"}}},
+		}
+		for _, name := range names {
+			n.Update = append(n.Update, &gnmi.Update{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "subinterface", Key: map[string]string{"index": name}}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}},
+			})
+		}
+		return []*gnmi.Notification{n}
+	}
+
+	first, deletes := pollDiff(nil, notification("0", "1"))
+	if len(first) != 2 || len(deletes) != 0 {
+		t.Fatalf("first poll: got %d paths and %d deletes, want 2 paths and no deletes", len(first), len(deletes))
+	}
+	second, deletes := pollDiff(first, notification("0"))
+	if len(second) != 1 || len(deletes) != 1 {
+		t.Fatalf("second poll: got %d paths and %d deletes, want 1 path and 1 delete", len(second), len(deletes))
+	}
+	if got, want := yparser.GnmiPath2XPath(deletes[0], true), "/interface/subinterface[index=1]"; got != want {
+		t.Errorf("second poll: delete = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,328 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/state/pkg/value"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+// processor processes a value of a state entry before it is published
+type processor interface {
+	// process processes the value at path p, the value is nil for deletes. It returns
+	// the processed path and value, and false if the value is dropped. The tags of the
+	// value are added to tags.
+	process(p *gnmi.Path, v *gnmi.TypedValue, tags map[string]string) (*gnmi.Path, *gnmi.TypedValue, bool)
+}
+
+// processors are the processors of a state entry in the order of their index
+type processors []processor
+
+// newProcessors creates the processors of a state entry
+func newProcessors(se *ygotnddpstate.YnddState_StateEntry) (processors, error) {
+	indexes := make([]uint32, 0, len(se.Processor))
+	for idx := range se.Processor {
+		indexes = append(indexes, idx)
+	}
+	sort.Slice(indexes, func(i, j int) bool { return indexes[i] < indexes[j] })
+
+	ps := make(processors, 0, len(indexes))
+	for _, idx := range indexes {
+		p, err := newProcessor(se.Processor[idx])
+		if err != nil {
+			return nil, fmt.Errorf("processor %d: %v", idx, err)
+		}
+		ps = append(ps, p)
+	}
+	return ps, nil
+}
+
+func newProcessor(cfg *ygotnddpstate.YnddState_StateEntry_Processor) (processor, error) {
+	m, err := newMatcher(cfg)
+	if err != nil {
+		return nil, err
+	}
+	switch cfg.Type {
+	case ygotnddpstate.YnddState_StateEntry_Processor_Type_drop:
+		return &dropProcessor{matcher: m}, nil
+	case ygotnddpstate.YnddState_StateEntry_Processor_Type_rename:
+		if m.path == nil || cfg.Replacement == nil {
+			return nil, fmt.Errorf("rename requires a path-match and a replacement")
+		}
+		return &renameProcessor{matcher: m, replacement: *cfg.Replacement}, nil
+	case ygotnddpstate.YnddState_StateEntry_Processor_Type_convert:
+		if cfg.ConvertTo == ygotnddpstate.YnddState_StateEntry_Processor_ConvertTo_UNSET {
+			return nil, fmt.Errorf("convert requires convert-to")
+		}
+		return &convertProcessor{matcher: m, to: cfg.ConvertTo}, nil
+	case ygotnddpstate.YnddState_StateEntry_Processor_Type_map_value:
+		vm := make(map[string]string, len(cfg.ValueMap))
+		for from, to := range cfg.ValueMap {
+			if to.To != nil {
+				vm[from] = *to.To
+			}
+		}
+		return &mapValueProcessor{matcher: m, values: vm}, nil
+	case ygotnddpstate.YnddState_StateEntry_Processor_Type_add_tag:
+		tags := make(map[string]string, len(cfg.Tag))
+		for name, t := range cfg.Tag {
+			if t.Value != nil {
+				tags[name] = *t.Value
+			}
+		}
+		return &addTagProcessor{matcher: m, staticTags: tags}, nil
+	}
+	return nil, fmt.Errorf("unknown processor type")
+}
+
+// valueTags are the tags the processors added to the values of a processed notification,
+// indexed like the updates and the deletes of the notification
+type valueTags struct {
+	update []map[string]string
+	delete []map[string]string
+}
+
+// updateTags returns the tags of the update at index i
+func (t *valueTags) updateTags(i int) map[string]string {
+	if t == nil {
+		return nil
+	}
+	return t.update[i]
+}
+
+// deleteTags returns the tags of the delete at index i
+func (t *valueTags) deleteTags(i int) map[string]string {
+	if t == nil {
+		return nil
+	}
+	return t.delete[i]
+}
+
+// apply runs the processors on a notification and returns the processed notification
+// and the tags the processors added to its values, the notification is returned
+// unchanged without tags when there are no processors. The paths of the processed
+// notification are absolute, the prefix only keeps the target.
+func (ps processors) apply(n *gnmi.Notification) (*gnmi.Notification, *valueTags) {
+	if len(ps) == 0 {
+		return n, nil
+	}
+	pn := &gnmi.Notification{
+		Timestamp: n.GetTimestamp(),
+		Prefix:    &gnmi.Path{Target: n.GetPrefix().GetTarget()},
+	}
+	vt := &valueTags{}
+	for _, u := range n.GetUpdate() {
+		p, v, tags, ok := ps.process(absolutePath(n.GetPrefix(), u.GetPath()), u.GetVal())
+		if ok {
+			pn.Update = append(pn.Update, &gnmi.Update{Path: p, Val: v, Duplicates: u.GetDuplicates()})
+			vt.update = append(vt.update, tags)
+		}
+	}
+	for _, d := range n.GetDelete() {
+		if p, _, tags, ok := ps.process(absolutePath(n.GetPrefix(), d), nil); ok {
+			pn.Delete = append(pn.Delete, p)
+			vt.delete = append(vt.delete, tags)
+		}
+	}
+	return pn, vt
+}
+
+// process runs the processors in order on the value at path p and returns the processed
+// path and value with the tags added by the processors, and false if the value is dropped
+func (ps processors) process(p *gnmi.Path, v *gnmi.TypedValue) (*gnmi.Path, *gnmi.TypedValue, map[string]string, bool) {
+	tags := map[string]string{}
+	for _, proc := range ps {
+		var ok bool
+		if p, v, ok = proc.process(p, v, tags); !ok {
+			return nil, nil, nil, false
+		}
+	}
+	return p, v, tags, true
+}
+
+// absolutePath returns the path p with the elements of the prefix
+func absolutePath(prefix, p *gnmi.Path) *gnmi.Path {
+	origin := p.GetOrigin()
+	if origin == "" {
+		origin = prefix.GetOrigin()
+	}
+	return &gnmi.Path{
+		Origin: origin,
+		Elem:   append(append([]*gnmi.PathElem{}, prefix.GetElem()...), p.GetElem()...),
+	}
+}
+
+// matcher selects the values a processor applies to by the xpath and the value
+type matcher struct {
+	path  *regexp.Regexp
+	value *regexp.Regexp
+}
+
+func newMatcher(cfg *ygotnddpstate.YnddState_StateEntry_Processor) (matcher, error) {
+	m := matcher{}
+	var err error
+	if cfg.PathMatch != nil && *cfg.PathMatch != "" {
+		if m.path, err = regexp.Compile(*cfg.PathMatch); err != nil {
+			return m, fmt.Errorf("invalid path-match: %v", err)
+		}
+	}
+	if cfg.ValueMatch != nil && *cfg.ValueMatch != "" {
+		if m.value, err = regexp.Compile(*cfg.ValueMatch); err != nil {
+			return m, fmt.Errorf("invalid value-match: %v", err)
+		}
+	}
+	return m, nil
+}
+
+// match returns true if the xpath and the value match, deletes only match a value-match
+// when there is none
+func (m matcher) match(xpath string, v *gnmi.TypedValue) bool {
+	if m.path != nil && !m.path.MatchString(xpath) {
+		return false
+	}
+	if m.value != nil {
+		if v == nil {
+			return false
+		}
+		s, ok := valueString(v)
+		if !ok || !m.value.MatchString(s) {
+			return false
+		}
+	}
+	return true
+}
+
+// valueString returns the string representation of a scalar value
+func valueString(v *gnmi.TypedValue) (string, bool) {
+	b, vt, err := value.ToBytes(v)
+	if err != nil || vt == value.TypeLeaflist || vt == value.TypeProto {
+		return "", false
+	}
+	s := string(b)
+	if vt == value.TypeJSON || vt == value.TypeJSONIETF {
+		// json strings are unquoted, other json values are used as is
+		if uq, err := strconv.Unquote(s); err == nil {
+			s = uq
+		}
+	}
+	return s, true
+}
+
+// dropProcessor drops the matching values
+type dropProcessor struct {
+	matcher
+}
+
+func (d *dropProcessor) process(p *gnmi.Path, v *gnmi.TypedValue, _ map[string]string) (*gnmi.Path, *gnmi.TypedValue, bool) {
+	if d.match(yparser.GnmiPath2XPath(p, true), v) {
+		return nil, nil, false
+	}
+	return p, v, true
+}
+
+// renameProcessor replaces the path-match in the xpath of the matching values
+type renameProcessor struct {
+	matcher
+	replacement string
+}
+
+func (r *renameProcessor) process(p *gnmi.Path, v *gnmi.TypedValue, _ map[string]string) (*gnmi.Path, *gnmi.TypedValue, bool) {
+	xpath := yparser.GnmiPath2XPath(p, true)
+	if !r.match(xpath, v) {
+		return p, v, true
+	}
+	np := yparser.Xpath2GnmiPath(r.path.ReplaceAllString(xpath, r.replacement), 0)
+	np.Origin = p.GetOrigin()
+	return np, v, true
+}
+
+// convertProcessor converts the matching string values to numbers, values that
+// cannot be converted are left unchanged
+type convertProcessor struct {
+	matcher
+	to ygotnddpstate.E_YnddState_StateEntry_Processor_ConvertTo
+}
+
+func (c *convertProcessor) process(p *gnmi.Path, v *gnmi.TypedValue, _ map[string]string) (*gnmi.Path, *gnmi.TypedValue, bool) {
+	if v == nil || !c.match(yparser.GnmiPath2XPath(p, true), v) {
+		return p, v, true
+	}
+	s, ok := valueString(v)
+	if !ok {
+		return p, v, true
+	}
+	s = strings.TrimSpace(s)
+	switch c.to {
+	case ygotnddpstate.YnddState_StateEntry_Processor_ConvertTo_int:
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return p, &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: i}}, true
+		}
+	case ygotnddpstate.YnddState_StateEntry_Processor_ConvertTo_uint:
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return p, &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: u}}, true
+		}
+	case ygotnddpstate.YnddState_StateEntry_Processor_ConvertTo_float:
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return p, &gnmi.TypedValue{Value: &gnmi.TypedValue_DoubleVal{DoubleVal: f}}, true
+		}
+	}
+	return p, v, true
+}
+
+// mapValueProcessor replaces the matching values that are in the value map
+type mapValueProcessor struct {
+	matcher
+	values map[string]string
+}
+
+func (m *mapValueProcessor) process(p *gnmi.Path, v *gnmi.TypedValue, _ map[string]string) (*gnmi.Path, *gnmi.TypedValue, bool) {
+	if v == nil || !m.match(yparser.GnmiPath2XPath(p, true), v) {
+		return p, v, true
+	}
+	s, ok := valueString(v)
+	if !ok {
+		return p, v, true
+	}
+	if to, ok := m.values[s]; ok {
+		return p, &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: to}}, true
+	}
+	return p, v, true
+}
+
+// addTagProcessor adds static tags to the matching values, the values are matched as
+// processed by the processors before it
+type addTagProcessor struct {
+	matcher
+	staticTags map[string]string
+}
+
+func (a *addTagProcessor) process(p *gnmi.Path, v *gnmi.TypedValue, tags map[string]string) (*gnmi.Path, *gnmi.TypedValue, bool) {
+	if a.match(yparser.GnmiPath2XPath(p, true), v) {
+		for k, tv := range a.staticTags {
+			tags[k] = tv
+		}
+	}
+	return p, v, true
+}
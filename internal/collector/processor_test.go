@@ -0,0 +1,111 @@
+package collector
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+func TestProcessors(t *testing.T) {
+	se := &ygotnddpstate.YnddState_StateEntry{Name: strPtr("itfce")}
+	drop, _ := se.NewProcessor(10)
+	drop.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_drop
+	drop.ValueMatch = strPtr("^disabled$")
+	rename, _ := se.NewProcessor(20)
+	rename.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_rename
+	rename.PathMatch = strPtr("oper-state$")
+	rename.Replacement = strPtr("status")
+	mapValue, _ := se.NewProcessor(30)
+	mapValue.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_map_value
+	mapValue.GetOrCreateValueMap("up").To = strPtr("1")
+	convert, _ := se.NewProcessor(40)
+	convert.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_convert
+	convert.ConvertTo = ygotnddpstate.YnddState_StateEntry_Processor_ConvertTo_int
+	ps, err := newProcessors(se)
+	if err != nil {
+		t.Fatalf("newProcessors: %v", err)
+	}
+
+	str := func(s string) *gnmi.TypedValue {
+		return &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: s}}
+	}
+	n, _ := ps.apply(&gnmi.Notification{
+		Prefix: &gnmi.Path{Target: "leaf1", Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "e1"}}}},
+		Update: []*gnmi.Update{
+			{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "oper-state"}}}, Val: str("up")},
+			{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "admin-state"}}}, Val: str("disabled")},
+		},
+	})
+	if len(n.GetUpdate()) != 1 {
+		t.Fatalf("got %d updates, want 1", len(n.GetUpdate()))
+	}
+	u := n.GetUpdate()[0]
+	if got, want := yparser.GnmiPath2XPath(u.GetPath(), true), "/interface[name=e1]/status"; got != want {
+		t.Errorf("path = %s, want %s", got, want)
+	}
+	if got := u.GetVal().GetIntVal(); got != 1 {
+		t.Errorf("value = %v, want int 1", u.GetVal())
+	}
+	if got := n.GetPrefix().GetTarget(); got != "leaf1" {
+		t.Errorf("prefix target = %s, want leaf1", got)
+	}
+}
+
+func TestNewProcessorsInvalid(t *testing.T) {
+	se := &ygotnddpstate.YnddState_StateEntry{Name: strPtr("itfce")}
+	p, _ := se.NewProcessor(1)
+	p.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_drop
+	p.PathMatch = strPtr("[")
+	if _, err := newProcessors(se); err == nil {
+		t.Errorf("newProcessors with invalid path-match: want error")
+	}
+}
+
+func TestProcessorsAddTagOrder(t *testing.T) {
+	se := &ygotnddpstate.YnddState_StateEntry{Name: strPtr("itfce")}
+	addTag, _ := se.NewProcessor(10)
+	addTag.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_add_tag
+	addTag.PathMatch = strPtr("oper-state$")
+	addTag.GetOrCreateTag("kind").Value = strPtr("oper")
+	rename, _ := se.NewProcessor(20)
+	rename.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_rename
+	rename.PathMatch = strPtr("oper-state$")
+	rename.Replacement = strPtr("status")
+	// matches the renamed path only, the rename runs after it
+	lateTag, _ := se.NewProcessor(5)
+	lateTag.Type = ygotnddpstate.YnddState_StateEntry_Processor_Type_add_tag
+	lateTag.PathMatch = strPtr("status$")
+	lateTag.GetOrCreateTag("renamed").Value = strPtr("true")
+	ps, err := newProcessors(se)
+	if err != nil {
+		t.Fatalf("newProcessors: %v", err)
+	}
+
+	n, vt := ps.apply(&gnmi.Notification{
+		Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "e1"}}}},
+		Update: []*gnmi.Update{
+			{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "oper-state"}}}, Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}}},
+			{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "admin-state"}}}, Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "enable"}}},
+		},
+	})
+	if len(n.GetUpdate()) != 2 {
+		t.Fatalf("got %d updates, want 2", len(n.GetUpdate()))
+	}
+	if got, want := yparser.GnmiPath2XPath(n.GetUpdate()[0].GetPath(), true), "/interface[name=e1]/status"; got != want {
+		t.Errorf("path = %s, want %s", got, want)
+	}
+	// the add-tag before the rename matched the original path, the one that
+	// precedes it in the chain did not see the renamed path
+	tags := vt.updateTags(0)
+	if tags["kind"] != "oper" {
+		t.Errorf("tags = %v, want kind=oper", tags)
+	}
+	if _, ok := tags["renamed"]; ok {
+		t.Errorf("tags = %v, want no renamed tag", tags)
+	}
+	if tags := vt.updateTags(1); len(tags) != 0 {
+		t.Errorf("tags of admin-state = %v, want none", tags)
+	}
+}
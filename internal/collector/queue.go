@@ -0,0 +1,129 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/yndd/pubsub"
+)
+
+// OverflowPolicy defines what happens when a message is queued in a full queue
+type OverflowPolicy string
+
+const (
+	// OverflowPolicyBlock blocks the gnmi receive loop until there is room in the queue
+	OverflowPolicyBlock OverflowPolicy = "block"
+	// OverflowPolicyDropOldest drops the oldest queued message to make room for the new one
+	OverflowPolicyDropOldest OverflowPolicy = "drop-oldest"
+	// OverflowPolicyDropNewest drops the new message
+	OverflowPolicyDropNewest OverflowPolicy = "drop-newest"
+
+	defaultQueueSize      = 1000
+	defaultOverflowPolicy = OverflowPolicyBlock
+
+	// errors
+	errUnknownOverflowPolicy = "unknown overflow policy"
+)
+
+// ValidateOverflowPolicy returns an error if the policy is not a known overflow policy
+func ValidateOverflowPolicy(p OverflowPolicy) error {
+	switch p {
+	case OverflowPolicyBlock, OverflowPolicyDropOldest, OverflowPolicyDropNewest:
+		return nil
+	}
+	return errors.Errorf("%s: %s", errUnknownOverflowPolicy, p)
+}
+
+// msgQueue is a bounded queue between the producers of a target, i.e. the gnmi receive
+// loop, the pollers and the stale watchers, and the publisher which is the single consumer
+type msgQueue struct {
+	target string
+	policy OverflowPolicy
+	ch     chan *pubsub.Msg
+}
+
+func newMsgQueue(target string, size int, policy OverflowPolicy) *msgQueue {
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	if policy == "" {
+		policy = defaultOverflowPolicy
+	}
+	return &msgQueue{
+		target: target,
+		policy: policy,
+		ch:     make(chan *pubsub.Msg, size),
+	}
+}
+
+// push queues the msg according to the overflow policy, it only returns an error
+// when the context is done while blocking on a full queue
+func (q *msgQueue) push(ctx context.Context, msg *pubsub.Msg) error {
+	defer q.observe()
+	switch q.policy {
+	case OverflowPolicyDropNewest:
+		select {
+		case q.ch <- msg:
+		default:
+			q.drop()
+		}
+		return nil
+	case OverflowPolicyDropOldest:
+		for {
+			select {
+			case q.ch <- msg:
+				return nil
+			default:
+				// the consumer or another producer might have taken the room in the meantime,
+				// every message that is removed is counted as dropped and the push is retried
+				select {
+				case <-q.ch:
+					q.drop()
+				default:
+				}
+			}
+		}
+	default:
+		select {
+		case q.ch <- msg:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// pop returns the channel the consumer reads the queued messages from
+func (q *msgQueue) pop() <-chan *pubsub.Msg {
+	return q.ch
+}
+
+func (q *msgQueue) drop() {
+	droppedMsgs.WithLabelValues(q.target, string(q.policy)).Inc()
+}
+
+func (q *msgQueue) observe() {
+	queueLength.WithLabelValues(q.target).Set(float64(len(q.ch)))
+}
+
+// delete removes the length of the queue, the dropped messages are kept until the
+// target is removed such that they survive the restarts of the target collector
+func (q *msgQueue) delete() {
+	queueLength.DeleteLabelValues(q.target)
+}
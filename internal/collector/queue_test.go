@@ -0,0 +1,58 @@
+package collector
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yndd/pubsub"
+)
+
+func TestMsgQueueDropOldestProducers(t *testing.T) {
+	const size, producers, msgs = 10, 4, 100
+	q := newMsgQueue("test/drop-oldest", size, OverflowPolicyDropOldest)
+	defer q.delete()
+	// the dropped counters are kept when the queue is deleted
+	defer deleteTargetMetrics(q.target, nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < producers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < msgs; j++ {
+				if err := q.push(context.Background(), &pubsub.Msg{}); err != nil {
+					t.Errorf("push() error = %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(q.pop()); got != size {
+		t.Errorf("queued = %d, want %d", got, size)
+	}
+	dropped := testutil.ToFloat64(droppedMsgs.WithLabelValues(q.target, string(q.policy)))
+	if want := float64(producers*msgs - size); dropped != want {
+		t.Errorf("dropped = %v, want %v", dropped, want)
+	}
+}
+
+func TestMsgQueueDroppedKept(t *testing.T) {
+	const target = "test/restart"
+	q := newMsgQueue(target, 1, OverflowPolicyDropNewest)
+	for i := 0; i < 3; i++ {
+		q.push(context.Background(), &pubsub.Msg{})
+	}
+	// the target collector is restarted
+	q.delete()
+	if dropped := testutil.ToFloat64(droppedMsgs.WithLabelValues(target, string(OverflowPolicyDropNewest))); dropped != 2 {
+		t.Errorf("dropped after restart = %v, want 2", dropped)
+	}
+	// the target is removed
+	deleteTargetMetrics(target, nil)
+	if dropped := testutil.ToFloat64(droppedMsgs.WithLabelValues(target, string(OverflowPolicyDropNewest))); dropped != 0 {
+		t.Errorf("dropped after removal = %v, want 0", dropped)
+	}
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package collector
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/pubsub"
+	statesubject "github.com/yndd/state/pkg/subject"
+	"github.com/yndd/state/pkg/value"
+)
+
+const (
+	// smallest interval the staleness of the paths of a state entry is checked with
+	minStaleCheckInterval = time.Second
+
+	// tag of the messages that republish the last value of a stale path
+	tagStale = "stale"
+
+	// errors
+	errInvalidStaleAfter = "invalid stale after"
+)
+
+// staleAfter returns the duration after which a path without update is stale, 0 if
+// staleness is not detected for the state entry
+func (s *Subscription) staleAfter() (time.Duration, error) {
+	if s.StateEntry.StaleAfter == nil || *s.StateEntry.StaleAfter == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(*s.StateEntry.StaleAfter)
+	if err != nil {
+		return 0, errors.Wrap(err, errInvalidStaleAfter)
+	}
+	return d, nil
+}
+
+// trackedPath is a path received for a state entry, before the processors are applied
+type trackedPath struct {
+	path *gnmi.Path
+	val  *gnmi.TypedValue
+	// time the last update or heartbeat of the path was received
+	lastSeen time.Time
+	// stale is set when the path is published as stale, it is cleared by the next update
+	stale bool
+	// seen is cleared when the subscription is restarted and set by the next update
+	seen bool
+}
+
+// pathTracker keeps the paths received for a state entry to detect stale paths and
+// the paths that did not reappear after a resync. It outlives the restarts of the
+// subscription.
+type pathTracker struct {
+	m     sync.Mutex
+	paths map[string]*trackedPath
+}
+
+func newPathTracker() *pathTracker {
+	return &pathTracker{paths: map[string]*trackedPath{}}
+}
+
+// update records the updates and deletes of a notification as received from the target
+func (t *pathTracker) update(n *gnmi.Notification, now time.Time) {
+	t.m.Lock()
+	defer t.m.Unlock()
+	for _, u := range n.GetUpdate() {
+		p := absolutePath(n.GetPrefix(), u.GetPath())
+		t.paths[yparser.GnmiPath2XPath(p, true)] = &trackedPath{
+			path:     p,
+			val:      u.GetVal(),
+			lastSeen: now,
+			seen:     true,
+		}
+	}
+	for _, d := range n.GetDelete() {
+		xpath := yparser.GnmiPath2XPath(absolutePath(n.GetPrefix(), d), true)
+		for k := range t.paths {
+			if k == xpath || strings.HasPrefix(k, xpath+"/") || strings.HasPrefix(k, xpath+"[") {
+				delete(t.paths, k)
+			}
+		}
+	}
+}
+
+// restart marks all paths as not seen since the restart of the subscription
+func (t *pathTracker) restart() {
+	t.m.Lock()
+	defer t.m.Unlock()
+	for _, tp := range t.paths {
+		tp.seen = false
+	}
+}
+
+// unseen returns the paths that were not received since the restart of the subscription
+func (t *pathTracker) unseen() []*gnmi.Path {
+	t.m.Lock()
+	defer t.m.Unlock()
+	paths := []*gnmi.Path{}
+	for _, tp := range t.paths {
+		if !tp.seen {
+			paths = append(paths, tp.path)
+		}
+	}
+	return paths
+}
+
+// stale marks the paths that were not received within the window as stale and returns
+// them, paths that are already stale are not returned again
+func (t *pathTracker) stale(window time.Duration, now time.Time) []trackedPath {
+	t.m.Lock()
+	defer t.m.Unlock()
+	stale := []trackedPath{}
+	for _, tp := range t.paths {
+		if !tp.stale && now.Sub(tp.lastSeen) > window {
+			tp.stale = true
+			stale = append(stale, *tp)
+		}
+	}
+	return stale
+}
+
+// watchStale publishes the paths of a state entry that were not received within the
+// window as stale until the subscription is stopped or replaced, i.e. until ctx is
+// canceled. It keeps running while the subscription is restarted since the values
+// become stale during that time.
+func (c *targetCollector) watchStale(ctx context.Context, s *Subscription, window time.Duration) {
+	interval := window / 2
+	if interval < minStaleCheckInterval {
+		interval = minStaleCheckInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		tps := s.paths.stale(window, time.Now())
+		if len(tps) > 0 {
+			c.log.Debug("stale paths", "target", c.target.Config.Name, "subscription", s.GetName(), "paths", len(tps))
+		}
+		for _, msg := range c.staleMsgs(c.target.Config.Name, s, tps) {
+			// a push that waits for room in the queue returns when the subscription
+			// is stopped or replaced
+			if ctx.Err() != nil {
+				return
+			}
+			if err := c.queue.push(ctx, msg); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// staleMsgs returns the messages that republish the last value of stale paths with the
+// stale tag, the processors of the state entry are applied to the values
+func (c *targetCollector) staleMsgs(targetName string, s *Subscription, tps []trackedPath) []*pubsub.Msg {
+	prefix := subjectPrefix(targetName, s)
+	tags := stateEntryTags(targetName, s)
+	now := time.Now().UnixNano()
+	result := make([]*pubsub.Msg, 0, len(tps))
+	for _, tp := range tps {
+		p, v, vtags, ok := s.processors.process(tp.path, tp.val)
+		if !ok {
+			continue
+		}
+		pr := statesubject.GNMIPathToSubject(p)
+		if pr == "" {
+			continue
+		}
+		b, vt, err := value.ToBytes(v)
+		if err != nil {
+			c.log.Debug("cannot convert value", "path", pr, "error", err)
+			continue
+		}
+		sm := &pubsub.Msg{
+			Subject:   fmt.Sprintf("%s.%s", prefix, pr),
+			Timestamp: now,
+			Operation: pubsub.Operation_OPERATION_UPDATE,
+			Data:      b,
+			Tags:      copyTags(tags),
+		}
+		c.addMessageTags(sm, p, vtags)
+		sm.Tags[tagValueType] = vt
+		sm.Tags[tagStale] = "true"
+		result = append(result, sm)
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+package collector
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/karimra/gnmic/target"
+	"github.com/karimra/gnmic/types"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/pubsub"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+func TestPathTracker(t *testing.T) {
+	notification := func(names ...string) *gnmi.Notification {
+		n := &gnmi.Notification{
+			Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface"}}},
+		}
+		for _, name := range names {
+			n.Update = append(n.Update, &gnmi.Update{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "subinterface", Key: map[string]string{"index": name}}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}},
+			})
+		}
+		return n
+	}
+	start := time.Now()
+	pt := newPathTracker()
+	pt.update(notification("0", "1"), start)
+
+	if stale := pt.stale(time.Minute, start.Add(30*time.Second)); len(stale) != 0 {
+		t.Errorf("stale within window: got %d paths, want none", len(stale))
+	}
+	pt.update(notification("0"), start.Add(time.Minute))
+	stale := pt.stale(time.Minute, start.Add(90*time.Second))
+	if len(stale) != 1 {
+		t.Fatalf("stale after window: got %d paths, want 1", len(stale))
+	}
+	if got, want := yparser.GnmiPath2XPath(stale[0].path, true), "/interface/subinterface[index=1]"; got != want {
+		t.Errorf("stale path = %s, want %s", got, want)
+	}
+	if stale := pt.stale(time.Minute, start.Add(100*time.Second)); len(stale) != 0 {
+		t.Errorf("stale again: got %d paths, want none", len(stale))
+	}
+
+	pt.restart()
+	pt.update(notification("1"), start.Add(2*time.Minute))
+	unseen := pt.unseen()
+	if len(unseen) != 1 {
+		t.Fatalf("unseen after resync: got %d paths, want 1", len(unseen))
+	}
+	if got, want := yparser.GnmiPath2XPath(unseen[0], true), "/interface/subinterface[index=0]"; got != want {
+		t.Errorf("unseen path = %s, want %s", got, want)
+	}
+}
+
+func TestWatchStaleStopsWhenReplaced(t *testing.T) {
+	c := &targetCollector{
+		target: target.NewTarget(&types.TargetConfig{Name: "default/leaf1"}),
+		queue:  newMsgQueue("default/leaf1", 1, OverflowPolicyBlock),
+		log:    logging.NewNopLogger(),
+	}
+	c.ctx, c.cfn = context.WithCancel(context.Background())
+	defer c.cfn()
+	defer c.queue.delete()
+
+	se := &ygotnddpstate.YnddState_StateEntry{Name: strPtr("itfce"), StaleAfter: strPtr("10ms")}
+	s := NewSubscription(se)
+	s.paths.update(&gnmi.Notification{Update: []*gnmi.Update{
+		{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "a"}}}, Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}}},
+		{Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "b"}}}, Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}}},
+	}}, time.Now().Add(-time.Minute))
+	// the queue is full, the watcher blocks when it publishes the stale paths
+	if err := c.queue.push(c.ctx, &pubsub.Msg{}); err != nil {
+		t.Fatalf("push() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(c.ctx)
+	done := make(chan struct{})
+	go func() {
+		c.watchStale(ctx, s, 10*time.Millisecond)
+		close(done)
+	}()
+	time.Sleep(minStaleCheckInterval + 100*time.Millisecond)
+	// the subscription is replaced, the collector keeps running
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("watcher of the replaced subscription did not stop")
+	}
+}
@@ -18,32 +18,202 @@ package collector
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	gapi "github.com/karimra/gnmic/api"
 	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
 	"github.com/yndd/ndd-yang/pkg/yparser"
+	"github.com/yndd/state/pkg/entrystatus"
 	"github.com/yndd/state/pkg/ygotnddpstate"
 )
 
-// Subscription defines the parameters for the subscription
+// Subscription defines the parameters for the subscription of a single state entry
 type Subscription struct {
-	Name        string
-	StateConfig *ygotnddpstate.Device
+	Name       string
+	StateEntry *ygotnddpstate.YnddState_StateEntry
+	// fingerprint of the canonical form of the state entry
+	fingerprint string
 
 	cfn context.CancelFunc
+	// id is the name of the gnmi subscription, unique for every (re)start of the subscription
+	// such that responses and errors of a previous run are not attributed to the current one
+	id string
+	// retrying is set while a restart of the failed subscription is scheduled
+	retrying bool
+	// retry state of the subscription, a failed subscription is restarted with its own
+	// backoff such that the failures of other subscriptions do not affect it
+	retry *retryState
+	// encoding the subscription is created with, the encoding of the state entry
+	// unless the target does not support it
+	encoding string
+	// sampleFallback is set when the target does not support the on-change or
+	// target defined mode of the state entry
+	sampleFallback bool
+	// processors applied to the notifications before they are published, a state
+	// entry with invalid processors is rejected
+	processors   processors
+	processorErr error
+	// changes keeps the last published values when the state entry publishes changes only
+	changes *changeTracker
+	// paths keeps the received paths when the state entry detects stale paths or
+	// deletes the paths that did not reappear after a resync
+	paths *pathTracker
+	// watching is set when the staleness of the paths is watched, the watcher runs
+	// until watchCfn is called when the subscription is stopped or replaced
+	watching bool
+	watchCfn context.CancelFunc
+	// status reported to the reconciler, it is updated from the receive loop
+	// and read from the gnmi server hence it has its own lock
+	sm     sync.RWMutex
+	status entrystatus.EntryStatus
+	// the state of the stream is updated from the receive loop, the poll goroutines
+	// and the (re)start of the subscription, it is guarded by the status lock.
+	// time the subscription was (re)started and whether it is synced since, used
+	// to measure the sync latency
+	startTime time.Time
+	synced    bool
+	// connected is set when the first response of the running stream is received,
+	// disconnected is set when the stream failed and cleared when it resynced
+	connected    bool
+	disconnected bool
+}
+
+// NewSubscription creates a subscription for a state entry, the subscription
+// is named after the state entry
+func NewSubscription(se *ygotnddpstate.YnddState_StateEntry) *Subscription {
+	ps, err := newProcessors(se)
+	s := &Subscription{
+		Name:         *se.Name,
+		StateEntry:   se,
+		fingerprint:  fingerprint(se),
+		processors:   ps,
+		processorErr: err,
+		status:       entrystatus.EntryStatus{Name: *se.Name},
+	}
+	if se.ChangesOnly != nil && *se.ChangesOnly {
+		s.changes = newChangeTracker()
+	}
+	if (se.StaleAfter != nil && *se.StaleAfter != "") || s.deleteAfterResync() {
+		s.paths = newPathTracker()
+	}
+	return s
+}
+
+// deleteAfterResync returns true if the paths that did not reappear after a resync are deleted
+func (s *Subscription) deleteAfterResync() bool {
+	return s.StateEntry.DeleteAfterResync != nil && *s.StateEntry.DeleteAfterResync
 }
 
 func (s *Subscription) GetName() string {
 	return s.Name
 }
 
+// GetID returns the name of the running gnmi subscription
+func (s *Subscription) GetID() string {
+	return s.id
+}
+
+// GetStatus returns the status of the subscription
+func (s *Subscription) GetStatus() entrystatus.EntryStatus {
+	s.sm.RLock()
+	defer s.sm.RUnlock()
+	return s.status
+}
+
+// setSubscribed marks the subscription as (re)started, its stream is not yet
+// connected nor synced
+func (s *Subscription) setSubscribed() {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	s.startTime = time.Now()
+	s.connected = false
+	s.synced = false
+	s.status.Subscribed = true
+	s.status.Synced = false
+}
+
+// setConnected marks the stream as connected, it returns true for the first response
+// of the running stream
+func (s *Subscription) setConnected() bool {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	if s.connected {
+		return false
+	}
+	s.connected = true
+	return true
+}
+
+// setSynced marks the initial sync of the subscription as completed. It returns true
+// for the first sync of the running stream, whether the stream resynced after it was
+// disconnected and the time the subscription was (re)started.
+func (s *Subscription) setSynced() (bool, bool, time.Time) {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	if s.synced {
+		return false, false, s.startTime
+	}
+	resynced := s.disconnected
+	s.synced = true
+	s.disconnected = false
+	s.status.Synced = true
+	return true, resynced, s.startTime
+}
+
+// setDisconnected marks the stream as disconnected, it returns true if the stream was
+// not disconnected yet
+func (s *Subscription) setDisconnected() bool {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	if s.disconnected {
+		return false
+	}
+	s.disconnected = true
+	return true
+}
+
+// setUpdated records the time of the last update received
+func (s *Subscription) setUpdated() {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	s.status.LastUpdate = time.Now()
+}
+
+// setRejected marks the subscription as not supported by the target
+func (s *Subscription) setRejected(msg string) {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	s.status.Subscribed = false
+	s.status.Synced = false
+	s.status.Rejected = true
+	s.status.Message = msg
+}
+
+// setDowngraded records the settings that are downgraded to what the target supports
+func (s *Subscription) setDowngraded(msg string) {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	s.status.Rejected = false
+	s.status.Message = msg
+}
+
+// setError marks the subscription as failed and records the error
+func (s *Subscription) setError(err error) {
+	s.sm.Lock()
+	defer s.sm.Unlock()
+	s.status.Subscribed = false
+	s.status.Synced = false
+	s.status.LastError = err.Error()
+	s.status.LastErrorTime = time.Now()
+}
+
 func (s *Subscription) GetPaths() []*gnmi.Path {
 	paths := []*gnmi.Path{}
 
-	for _, m := range s.StateConfig.StateEntry {
-		for _, p := range m.Path {
-			paths = append(paths, yparser.Xpath2GnmiPath(p, 0))
-		}
+	for _, p := range s.StateEntry.Path {
+		paths = append(paths, yparser.Xpath2GnmiPath(p, 0))
 	}
 	return paths
 }
@@ -56,22 +226,75 @@ func (s *Subscription) SetCancelFn(c context.CancelFunc) {
 	s.cfn = c
 }
 
+// GetEncoding returns the encoding of the subscription, ascii is used if the
+// state entry does not define an encoding
+func (s *Subscription) GetEncoding() string {
+	if s.StateEntry.Encoding == ygotnddpstate.YnddState_StateEntry_Encoding_UNSET {
+		return "ascii"
+	}
+	return ygotnddpstate.ΛEnum["E_YnddState_StateEntry_Encoding"][int64(s.StateEntry.Encoding)].Name
+}
+
+// getSubscribeEncoding returns the encoding the subscription is created with
+func (s *Subscription) getSubscribeEncoding() string {
+	if s.encoding != "" {
+		return s.encoding
+	}
+	return s.GetEncoding()
+}
+
 // createSubscribeRequest create a gnmi subscription
 func (s *Subscription) createSubscribeRequest() (*gnmi.SubscribeRequest, error) {
+	// the subscription options are the same for every path of the state entry
+	subOpts, err := s.subscriptionOptions()
+	if err != nil {
+		return nil, err
+	}
 	// create subscription
 	gnmiOpts := []gapi.GNMIOption{
 		gapi.SubscriptionListModeSTREAM(),
-		gapi.EncodingASCII(),
-	}
-	for _, st := range s.StateConfig.StateEntry {
-		for _, p := range st.Path {
-			gnmiOpts = append(gnmiOpts,
-				gapi.Subscription(
-					gapi.Path(p),
-					gapi.SubscriptionModeON_CHANGE(),
-				),
-			)
-		}
+		gapi.Encoding(s.getSubscribeEncoding()),
+	}
+	for _, p := range s.StateEntry.Path {
+		gnmiOpts = append(gnmiOpts,
+			gapi.Subscription(
+				append([]gapi.GNMIOption{gapi.Path(p)}, subOpts...)...,
+			),
+		)
 	}
 	return gapi.NewSubscribeRequest(gnmiOpts...)
 }
+
+// subscriptionOptions returns the subscription mode, sample interval, heartbeat interval
+// and suppress redundant options of the state entry
+func (s *Subscription) subscriptionOptions() ([]gapi.GNMIOption, error) {
+	se := s.StateEntry
+	opts := []gapi.GNMIOption{}
+	switch {
+	case se.Mode == ygotnddpstate.YnddState_StateEntry_Mode_sample, s.sampleFallback:
+		opts = append(opts, gapi.SubscriptionModeSAMPLE())
+	case se.Mode == ygotnddpstate.YnddState_StateEntry_Mode_target_defined:
+		opts = append(opts, gapi.SubscriptionModeTARGET_DEFINED())
+	default:
+		// on-change is the default mode
+		opts = append(opts, gapi.SubscriptionModeON_CHANGE())
+	}
+	if se.SampleInterval != nil {
+		d, err := time.ParseDuration(*se.SampleInterval)
+		if err != nil {
+			return nil, errors.Wrap(err, errInvalidSampleInterval)
+		}
+		opts = append(opts, gapi.SampleInterval(d))
+	}
+	if se.HeartbeatInterval != nil {
+		d, err := time.ParseDuration(*se.HeartbeatInterval)
+		if err != nil {
+			return nil, errors.Wrap(err, errInvalidHeartbeatInterval)
+		}
+		opts = append(opts, gapi.HeartbeatInterval(d))
+	}
+	if se.SuppressRedundant != nil && *se.SuppressRedundant {
+		opts = append(opts, gapi.SuppressRedundant(true))
+	}
+	return opts, nil
+}
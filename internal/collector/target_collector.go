@@ -18,16 +18,23 @@ package collector
 
 import (
 	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/karimra/gnmic/target"
 	"github.com/karimra/gnmic/types"
+	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/pkg/errors"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/pubsub"
-	"github.com/yndd/pubsub/publisher"
+	"github.com/yndd/state/internal/lastvalue"
+	"github.com/yndd/state/internal/promexporter"
+	"github.com/yndd/state/pkg/entrystatus"
 	"github.com/yndd/state/pkg/ygotnddpstate"
-	"google.golang.org/grpc"
+	utilerrors "k8s.io/apimachinery/pkg/util/errors"
 )
 
 const (
@@ -39,16 +46,32 @@ const (
 	// mq
 	streamName     = "nddpstate"
 	streamSubjects = "nddpstate.>"
+	// message tags
+	tagTarget     = "target"
+	tagStateEntry = "state-entry"
+	tagPrefix     = "prefix"
+	tagValueType  = "value-type"
 
 	// errors
 	errCreateGnmiClient          = "cannot create gnmi client"
 	errCreateSubscriptionRequest = "cannot create subscription request"
+	errInvalidSampleInterval     = "invalid sample interval"
+	errInvalidHeartbeatInterval  = "invalid heartbeat interval"
 )
 
 // TargetCollector defines the interfaces for the collector
 type TargetCollector interface {
 	Start(ctx context.Context) error
 	Stop() error
+	// ReconcileSubscriptions aligns the running subscriptions with the state entries of the config
+	ReconcileSubscriptions(mc *ygotnddpstate.Device) error
+	// GetEntryStatus returns the status of the subscriptions indexed by state entry name
+	GetEntryStatus() map[string]entrystatus.EntryStatus
+	// HasConnectionConfig returns true if the target collector is connected with the
+	// connection parameters of the target config
+	HasConnectionConfig(tc *types.TargetConfig) bool
+	// IsConnected returns true once the gnmi client of the target is created
+	IsConnected() bool
 }
 
 // Option can be used to manipulate TargetCollector.
@@ -61,9 +84,48 @@ func WithTargetCollectorLogger(log logging.Logger) TargetCollectorOption {
 	}
 }
 
-func WithTargetCollectorMQAddr(addr string) TargetCollectorOption {
+// WithTargetCollectorQueue specifies the size and the overflow policy of the queue
+// between the gnmi receive loop and the publisher.
+func WithTargetCollectorQueue(size int, policy OverflowPolicy) TargetCollectorOption {
 	return func(o *targetCollector) {
-		o.mqAddr = addr
+		o.queueSize = size
+		o.overflowPolicy = policy
+	}
+}
+
+// WithTargetCollectorMessageFormat specifies the format of the published messages.
+func WithTargetCollectorMessageFormat(f MessageFormat) TargetCollectorOption {
+	return func(o *targetCollector) {
+		o.messageFormat = f
+	}
+}
+
+// WithTargetCollectorLastValueCache specifies the cache that keeps the last collected values.
+func WithTargetCollectorLastValueCache(lvc lastvalue.Cache) TargetCollectorOption {
+	return func(o *targetCollector) {
+		o.lastValueCache = lvc
+	}
+}
+
+// WithTargetCollectorExporter specifies the exporter that exposes the collected numeric values.
+func WithTargetCollectorExporter(e promexporter.Exporter) TargetCollectorOption {
+	return func(o *targetCollector) {
+		o.exporter = e
+	}
+}
+
+// withTargetCollectorRetryState specifies the retry state of the target, it is
+// owned by the collector such that it survives the target collector.
+func withTargetCollectorRetryState(r *retryState) TargetCollectorOption {
+	return func(o *targetCollector) {
+		o.retry = r
+	}
+}
+
+// WithTargetCollectorOutputs specifies the outputs the collected state is published to.
+func WithTargetCollectorOutputs(outputs []Output) TargetCollectorOption {
+	return func(o *targetCollector) {
+		o.outputs = outputs
 	}
 }
 
@@ -71,12 +133,39 @@ func WithTargetCollectorMQAddr(addr string) TargetCollectorOption {
 type targetCollector struct {
 	// target the state is collected from
 	target *target.Target
-	// update channel pubsub publisher goroutines read from
-	updateCh chan *pubsub.Msg
-	// comma separated mq server addresses
-	mqAddr string
-	// subscriptions derived from State CR
-	subscriptions []*Subscription
+	// models and encodings supported by the target
+	capabilities *capabilities
+	// bounded queue the publisher goroutine reads from
+	queue          *msgQueue
+	queueSize      int
+	overflowPolicy OverflowPolicy
+	// format of the published messages
+	messageFormat MessageFormat
+	// outputs the messages are published to
+	outputs []Output
+	// cache that keeps the last collected values, optional
+	lastValueCache lastvalue.Cache
+	// exporter that exposes the collected numeric values as prometheus metrics, optional
+	exporter promexporter.Exporter
+	// subscriptions derived from State CR, indexed by state entry name
+	m             sync.RWMutex
+	subscriptions map[string]*Subscription
+	// sequence number used to create unique subscription ids
+	subscriptionSeq uint64
+	// fingerprint of the state entries the subscriptions were reconciled with
+	fingerprint string
+	// connected is set once the gnmi client is created, the state entries reconciled
+	// before are kept in desired and applied when the target is connected
+	connected bool
+	desired   *ygotnddpstate.Device
+	// connectivity of the target as published in the target connectivity events
+	connectivity targetConnectivity
+	// retry state of the connection to the target, failed dials and lost connections
+	// are recorded, failures of single subscriptions are recorded in their own state
+	retry *retryState
+	// context the subscriptions are derived from, canceled when the collector stops
+	ctx context.Context
+	cfn context.CancelFunc
 	// channel to signal stopping of the state collector
 	stopCh chan struct{}
 	// logger
@@ -84,22 +173,19 @@ type targetCollector struct {
 }
 
 // NewTargetCollector creates a new GNMI collector for a target defined by target config tc,
-// this function creates the gNMI client as well.
-func NewTargetCollector(ctx context.Context, tc *types.TargetConfig, mc *ygotnddpstate.Device, opts ...TargetCollectorOption) (TargetCollector, error) {
+// the gNMI client is created when the target collector is started.
+func NewTargetCollector(ctx context.Context, tc *types.TargetConfig, opts ...TargetCollectorOption) TargetCollector {
 	sc := &targetCollector{
-		// TODO: add second subscription
-		subscriptions: []*Subscription{
-			{
-				Name:        "target-collector",
-				StateConfig: mc,
-			},
-		},
-		updateCh: make(chan *pubsub.Msg),
-		stopCh:   make(chan struct{}),
+		subscriptions: map[string]*Subscription{},
+		stopCh:        make(chan struct{}),
 	}
 	for _, opt := range opts {
 		opt(sc)
 	}
+	sc.queue = newMsgQueue(tc.Name, sc.queueSize, sc.overflowPolicy)
+	if sc.retry == nil {
+		sc.retry = newRetryState(DefaultBackoff())
+	}
 	if tc.BufferSize == 0 {
 		tc.BufferSize = defaultTargetReceiveBuffer
 	}
@@ -107,30 +193,213 @@ func NewTargetCollector(ctx context.Context, tc *types.TargetConfig, mc *ygotndd
 		tc.RetryTimer = defaultRetryTimer
 	}
 	sc.target = target.NewTarget(tc)
-	if err := sc.target.CreateGNMIClient(ctx, grpc.WithBlock()); err != nil { // TODO add dialopts
-		return nil, errors.Wrap(err, errCreateGnmiClient)
+	sc.ctx, sc.cfn = context.WithCancel(ctx)
+
+	return sc
+}
+
+// connect creates the gnmi client of the target, a failed dial is retried with the
+// backoff of the target until it succeeds or the target collector is stopped. It
+// returns false if the target collector is stopped before the target is connected.
+func (c *targetCollector) connect() bool {
+	log := c.log.WithValues("Target", c.target.Config.Name, "Address", c.target.Config.Address)
+	for {
+		// the dial options, e.g. tls and credentials, are derived from the target config
+		err := c.target.CreateGNMIClient(c.ctx)
+		if err == nil {
+			break
+		}
+		if c.ctx.Err() != nil {
+			return false
+		}
+		dialFailures.WithLabelValues(c.target.Config.Name).Inc()
+		err = errors.Wrap(err, errCreateGnmiClient)
+		c.retry.failure(err)
+		if c.publishTargetConnectivity(ConnectivityDisconnected, err) != nil {
+			return false
+		}
+		wait := c.retry.wait()
+		log.Debug(errCreateGnmiClient, "error", err, "retry in", wait)
+		select {
+		case <-time.After(wait):
+		case <-c.ctx.Done():
+			return false
+		}
 	}
+	c.retry.success()
+	if c.publishTargetConnectivity(ConnectivityConnected, nil) != nil {
+		return false
+	}
+	caps := c.getCapabilities(c.ctx)
+
+	// the state entries reconciled while the target was not connected are applied
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.capabilities = caps
+	c.connected = true
+	if c.desired != nil {
+		if err := c.reconcileSubscriptions(c.desired); err != nil {
+			log.Debug("cannot reconcile subscriptions", "error", err)
+		}
+	}
+	return true
+}
 
-	return sc, nil
+// getCapabilities returns the capabilities of the target, nil if the target does not
+// report them in which case the subscriptions are not validated
+func (c *targetCollector) getCapabilities(ctx context.Context) *capabilities {
+	timeout := c.target.Config.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	resp, err := c.target.Capabilities(ctx)
+	if err != nil {
+		c.log.Debug("cannot get capabilities, subscriptions are not validated", "target", c.target.Config.Name, "error", err)
+		return nil
+	}
+	c.log.Debug("capabilities", "target", c.target.Config.Name,
+		"encodings", resp.GetSupportedEncodings(), "models", len(resp.GetSupportedModels()))
+	return newCapabilities(resp)
+}
+
+// IsConnected returns true once the gnmi client of the target is created
+func (c *targetCollector) IsConnected() bool {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.connected
 }
 
-// Lock locks a gnmi collector
 func (c *targetCollector) GetTarget() *target.Target {
 	return c.target
 }
 
-// GetSubscription returns a bool based on a subscription name
+func (c *targetCollector) HasConnectionConfig(tc *types.TargetConfig) bool {
+	return reflect.DeepEqual(connectionConfig(c.target.Config), connectionConfig(tc))
+}
+
+// connectionConfig returns a copy of the target config with only the parameters
+// that require a new gnmi client when they change
+func connectionConfig(tc *types.TargetConfig) types.TargetConfig {
+	cc := *tc
+	cc.Subscriptions = nil
+	cc.Outputs = nil
+	cc.BufferSize = 0
+	cc.RetryTimer = 0
+	cc.Tags = nil
+	return cc
+}
+
+// GetSubscriptions returns the running subscriptions
 func (c *targetCollector) GetSubscriptions() []*Subscription {
-	return c.subscriptions
+	c.m.RLock()
+	defer c.m.RUnlock()
+	subs := make([]*Subscription, 0, len(c.subscriptions))
+	for _, s := range c.subscriptions {
+		subs = append(subs, s)
+	}
+	return subs
 }
 
-// GetSubscription returns a bool based on a subscription name
+// GetSubscription returns a subscription based on a subscription name
 func (c *targetCollector) GetSubscription(subName string) *Subscription {
-	for _, s := range c.GetSubscriptions() {
-		if s.GetName() == subName {
-			return s
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.subscriptions[subName]
+}
+
+// GetEntryStatus returns the status of the subscriptions indexed by state entry name
+func (c *targetCollector) GetEntryStatus() map[string]entrystatus.EntryStatus {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	es := make(map[string]entrystatus.EntryStatus, len(c.subscriptions))
+	for name, s := range c.subscriptions {
+		es[name] = s.GetStatus()
+	}
+	return es
+}
+
+// getSubscriptionByID returns the subscription with the gnmi subscription id, nil if
+// the subscription was stopped or restarted in the meantime
+func (c *targetCollector) getSubscriptionByID(id string) *Subscription {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return c.subscriptionByID(id)
+}
+
+// subscriptionByID is getSubscriptionByID for callers that hold the lock
+func (c *targetCollector) subscriptionByID(id string) *Subscription {
+	i := strings.LastIndex(id, "@")
+	if i < 0 {
+		return nil
+	}
+	s := c.subscriptions[id[:i]]
+	if s == nil || s.GetID() != id {
+		return nil
+	}
+	return s
+}
+
+// ReconcileSubscriptions starts a subscription for every new state entry, stops the
+// subscriptions of the state entries that no longer exist and restarts the
+// subscriptions of the state entries that changed. State entries are compared by
+// the fingerprint of their canonical form, subscriptions of unchanged state entries
+// are not touched and nothing is done when the fingerprint of the target is unchanged.
+func (c *targetCollector) ReconcileSubscriptions(mc *ygotnddpstate.Device) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.desired = mc
+	if !c.connected {
+		c.log.Debug("target not connected, state entries are reconciled when connected", "target", c.target.Config.Name)
+		return nil
+	}
+	return c.reconcileSubscriptions(mc)
+}
+
+// reconcileSubscriptions is ReconcileSubscriptions for callers that hold the lock
+func (c *targetCollector) reconcileSubscriptions(mc *ygotnddpstate.Device) error {
+	fp := targetFingerprint(mc)
+	if fp != "" && fp == c.fingerprint {
+		c.log.Debug("state entries unchanged", "target", c.target.Config.Name)
+		return nil
+	}
+	// the fingerprint is only recorded when all subscriptions are reconciled
+	c.fingerprint = ""
+
+	for name, s := range c.subscriptions {
+		if _, ok := mc.StateEntry[name]; !ok {
+			c.stopSubscription(s)
+			c.deleteLastValues(s)
+			deleteSubscriptionMetrics(c.target.Config.Name, name)
+			delete(c.subscriptions, name)
 		}
 	}
+
+	errs := []error{}
+	for name, se := range mc.StateEntry {
+		if s, ok := c.subscriptions[name]; ok {
+			if s.fingerprint != "" && s.fingerprint == fingerprint(se) {
+				continue
+			}
+			c.stopSubscription(s)
+			c.deleteLastValues(s)
+		}
+		s := NewSubscription(se)
+		if err := c.startSubscription(s); err != nil {
+			// the state entry is kept such that the error is reported and does not
+			// affect the other entries, it is started again on the next reconcile
+			s.setError(err)
+			s.setRejected(err.Error())
+			s.fingerprint = ""
+			errs = append(errs, errors.Wrapf(err, "state entry %s", name))
+		}
+		c.subscriptions[name] = s
+	}
+	if len(errs) > 0 {
+		return utilerrors.NewAggregate(errs)
+	}
+	c.fingerprint = fp
 	return nil
 }
 
@@ -139,29 +408,19 @@ func (c *targetCollector) Start(ctx context.Context) error {
 	log := c.log.WithValues("Target", c.target.Config.Name, "Address", c.target.Config.Address)
 	log.Debug("Starting target collector", "target", c.target.Config.Name)
 
+	if c.lastValueCache != nil {
+		c.lastValueCache.AddTarget(c.target.Config.Name)
+	}
+
 	go c.publisherWorker(ctx)
 
 	go func() {
-		for {
-			select {
-			case <-ctx.Done():
-				return
-			default:
-				err := c.run(ctx)
-				switch err {
-				case nil:
-					// stop channel closed
-					return
-				case context.Canceled:
-					// target collector stopped
-					return
-				default:
-					log.Debug("error starting target collector", "error", err)
-					time.Sleep(time.Second)
-					continue
-				}
-			}
+		if !c.connect() {
+			return
 		}
+		// failed subscriptions are restarted with backoff by the receive loop itself,
+		// it only returns when the target collector is stopped
+		c.run(ctx)
 	}()
 	return nil
 }
@@ -171,88 +430,315 @@ func (c *targetCollector) run(ctx context.Context) error {
 	log := c.log.WithValues("Target", c.target.Config.Name, "Address", c.target.Config.Address)
 	log.Debug("Running target collector...")
 
-	ctx, c.subscriptions[0].cfn = context.WithCancel(ctx)
-
-	// this subscription is a go routine that runs until the cancel function is called
-	go c.startSubscription(ctx)
-
 	chanSubResp, chanSubErr := c.GetTarget().ReadSubscriptions()
 	// run the response handler loop
 	for {
 		select {
 		// subscribe response or error cases
 		case resp := <-chanSubResp:
-			c.handleSubscribeResponse(resp.Response)
+			s := c.getSubscriptionByID(resp.SubscriptionName)
+			if s == nil {
+				// response of a subscription that was stopped in the meantime
+				continue
+			}
+			c.handleSubscribeResponse(s, resp.Response)
 		case tErr := <-chanSubErr:
-			c.log.Debug("subscribe", "error", tErr)
-			return errors.New("handle subscription error")
+			// the failed subscription is restarted with backoff, other subscriptions are not affected
+			c.log.Debug("subscribe", "subscription", tErr.SubscriptionName, "error", tErr.Err)
+			c.handleSubscriptionError(tErr.SubscriptionName, tErr.Err)
 
 		// stop cases
-		// canceled when the subscription is stopped
-		case <-ctx.Done(): // canceled when the subscription is stopped
-			c.log.Debug("subscription stopped", "error", ctx.Err())
+		// the collector context is canceled
+		case <-ctx.Done():
+			c.log.Debug("target collector stopped", "error", ctx.Err())
+			go drainSubscriptions(chanSubResp, chanSubErr, defaultTimeout)
 			return ctx.Err()
 		// the whole target collector is stopped
 		case <-c.stopCh: // the whole target collector is stopped
-			c.stopSubscription(c.subscriptions[0])
 			c.log.Debug("Stopping target collector process...")
+			go drainSubscriptions(chanSubResp, chanSubErr, defaultTimeout)
 			return nil
 		}
 	}
 }
 
-// StartSubscription starts a subscription
-func (c *targetCollector) startSubscription(ctx context.Context) error {
-	s := c.GetSubscriptions()
-	log := c.log.WithValues("subscription", s[0].GetName(), "Paths", s[0].GetPaths())
+// drainSubscriptions discards the responses and errors the canceled gnmi subscriptions
+// send after the receive loop stopped, the gnmi target sends the errors on an unbuffered
+// channel and its subscription goroutines would block forever otherwise
+func drainSubscriptions(chanSubResp <-chan *target.SubscribeResponse, chanSubErr <-chan *target.TargetError, d time.Duration) {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	for {
+		select {
+		case <-chanSubResp:
+		case <-chanSubErr:
+		case <-timer.C:
+			return
+		}
+	}
+}
+
+// startSubscription starts a subscription, the caller must hold the lock
+func (c *targetCollector) startSubscription(s *Subscription) error {
+	log := c.log.WithValues("subscription", s.GetName(), "Paths", s.GetPaths())
 	log.Debug("subscription starting", "target", c.target.Config.Name)
-	// create subscription request
-	req, err := s[0].createSubscribeRequest()
+	if s.processorErr != nil {
+		log.Debug("subscription rejected", "error", s.processorErr)
+		s.setRejected(s.processorErr.Error())
+		return nil
+	}
+	// validate the subscription against the capabilities of the target, a state entry
+	// the target does not support is not subscribed to until it changes
+	encoding, msg, err := c.capabilities.check(s)
 	if err != nil {
-		c.log.Debug(errCreateSubscriptionRequest, "error", err)
-		return errors.Wrap(err, errCreateSubscriptionRequest)
+		log.Debug("subscription rejected", "error", err)
+		s.setRejected(err.Error())
+		return nil
+	}
+	s.encoding = encoding
+	msgs := []string{}
+	if msg != "" {
+		msgs = append(msgs, msg)
+	}
+	if s.sampleFallback {
+		msgs = append(msgs, "mode not supported by the target, using sample")
+	}
+	s.setDowngraded(strings.Join(msgs, "; "))
+	staleAfter, err := s.staleAfter()
+	if err != nil {
+		c.log.Debug(errInvalidStaleAfter, "error", err)
+		return err
+	}
+	var run func(ctx context.Context, id string)
+	if s.isPoll() {
+		// create get request, the state entry is polled instead of subscribed to
+		interval, err := s.pollInterval()
+		if err != nil {
+			c.log.Debug(errCreateGetRequest, "error", err)
+			return errors.Wrap(err, errCreateGetRequest)
+		}
+		req, err := s.createGetRequest()
+		if err != nil {
+			c.log.Debug(errCreateGetRequest, "error", err)
+			return errors.Wrap(err, errCreateGetRequest)
+		}
+		log.Debug("Poll", "Request", req, "interval", interval)
+		run = func(ctx context.Context, id string) {
+			c.poll(ctx, s, id, req, interval)
+		}
+	} else {
+		// create subscription request
+		req, err := s.createSubscribeRequest()
+		if err != nil {
+			c.log.Debug(errCreateSubscriptionRequest, "error", err)
+			return errors.Wrap(err, errCreateSubscriptionRequest)
+		}
+		log.Debug("Subscription", "Request", req)
+		run = func(ctx context.Context, id string) {
+			c.subscribe(ctx, req, id)
+		}
 	}
 
-	log.Debug("Subscription", "Request", req)
-	go c.target.Subscribe(ctx, req, s[0].GetName())
+	var ctx context.Context
+	ctx, s.cfn = context.WithCancel(c.ctx)
+	c.subscriptionSeq++
+	s.id = fmt.Sprintf("%s@%d", s.GetName(), c.subscriptionSeq)
+	if s.retry == nil {
+		s.retry = newRetryState(c.retry.backoff)
+	}
+	s.setSubscribed()
+	if staleAfter > 0 && !s.watching {
+		s.watching = true
+		var watchCtx context.Context
+		watchCtx, s.watchCfn = context.WithCancel(c.ctx)
+		go c.watchStale(watchCtx, s, staleAfter)
+	}
+	// this subscription is a go routine that runs until the cancel function is called
+	go run(ctx, s.GetID())
 	log.Debug("subscription started", "target", c.target.Config.Name)
 	return nil
 }
 
+// subscribe runs a gnmi subscription until the context is canceled. Every run of a
+// subscription has its own id, the gnmi target keeps the subscribe client of an id
+// until it is stopped, so it is removed from the gnmi target when the run returns.
+func (c *targetCollector) subscribe(ctx context.Context, req *gnmi.SubscribeRequest, id string) {
+	c.target.Subscribe(ctx, req, id)
+	forgetSubscription(c.target, id)
+}
+
+// forgetSubscription removes a returned subscription from the gnmi target, the gnmi target
+// only knows the subscriptions that created a subscribe client and panics on the others
+func forgetSubscription(t *target.Target, id string) {
+	defer func() {
+		recover()
+	}()
+	t.StopSubscription(id)
+}
+
 // Stop stops the target collector
 func (c *targetCollector) Stop() error {
 	log := c.log.WithValues("Target", c.GetTarget().Config.Name)
 	log.Debug("Stoping target collector...", "target", c.target.Config.Name)
 
-	c.stopSubscription(c.GetSubscriptions()[0])
+	c.publishTargetStopped()
 	close(c.stopCh)
+	// unblocks the receive loop and the watchers that wait for room in the queue, before
+	// the lock is taken such that stopping does not wait for the queue
+	c.cfn()
+	c.m.Lock()
+	for name, s := range c.subscriptions {
+		c.stopSubscription(s)
+		delete(c.subscriptions, name)
+	}
+	c.m.Unlock()
+	// the target collector is recreated when the target reconnects, its gnmi connection
+	// and the gnmi subscriptions are closed
+	if err := c.target.Close(); err != nil {
+		log.Debug("cannot close target", "error", err)
+	}
+	c.queue.delete()
+	if c.lastValueCache != nil {
+		c.lastValueCache.RemoveTarget(c.target.Config.Name)
+	}
+	if c.exporter != nil {
+		c.exporter.DeleteTarget(c.target.Config.Name)
+	}
 
 	return nil
 }
 
-// StopSubscription stops a subscription
+// stopSubscription stops a subscription, the caller must hold the lock
 func (c *targetCollector) stopSubscription(s *Subscription) error {
-	c.log.Debug("subscription stop...")
-	s.cfn()
-	c.log.Debug("subscription stopped")
+	c.log.Debug("subscription stop...", "subscription", s.GetName())
+	if s.cfn != nil {
+		s.cfn()
+	}
+	// the staleness watcher of a stopped or replaced subscription stops as well
+	if s.watchCfn != nil {
+		s.watchCfn()
+	}
+	c.log.Debug("subscription stopped", "subscription", s.GetName())
 	return nil
 }
 
-func (c *targetCollector) publisherWorker(ctx context.Context) {
-STARTCONN:
-	select {
-	case <-ctx.Done():
-		c.log.Debug("publisher stopped", "error", ctx.Err())
+// handleSubscriptionError stops the failed subscription and schedules its restart
+// according to the retry state of the subscription. The gnmi target reports a failure
+// with multiple errors, only the first one is handled.
+func (c *targetCollector) handleSubscriptionError(id string, err error) {
+	// the disconnected event is queued without holding the lock, the queue blocks
+	// when it is full until the collector is stopped
+	if s := c.failSubscription(id, err); s != nil {
+		if c.publishConnectivity(s, ConnectivityDisconnected, err) != nil {
+			return
+		}
+		if isTransportFailure(err) {
+			// the connection to the target is lost
+			c.publishTargetConnectivity(ConnectivityDisconnected, err)
+		}
+	}
+}
+
+// failSubscription stops the failed subscription and schedules its restart, it returns
+// the subscription if its stream is disconnected by the failure
+func (c *targetCollector) failSubscription(id string, err error) *Subscription {
+	c.m.Lock()
+	defer c.m.Unlock()
+	s := c.subscriptionByID(id)
+	if s == nil || s.retrying {
+		// error of a subscription that was stopped, restarted or is already retried
+		return nil
+	}
+	// stop the retry loop of the gnmi target, the subscription is restarted with backoff
+	s.cfn()
+	if isUnsupported(err) {
+		// the target rejects the subscription, the on-change and target defined modes fall
+		// back to sample, a rejected sample subscription or poll is not retried until the
+		// state entry changes
+		if s.sampleFallback || s.isPoll() || s.StateEntry.Mode == ygotnddpstate.YnddState_StateEntry_Mode_sample {
+			c.log.Debug("subscription rejected", "subscription", s.GetName(), "error", err)
+			s.id = ""
+			s.setError(err)
+			s.setRejected(fmt.Sprintf("subscription not supported by the target: %v", err))
+			return nil
+		}
+		s.sampleFallback = true
+	}
+	reconnects.WithLabelValues(c.target.Config.Name, s.GetName()).Inc()
+	s.retrying = true
+	s.setError(err)
+	disconnected := s.setDisconnected()
+	if disconnected {
+		if s.paths != nil {
+			s.paths.restart()
+		}
+	}
+	if isTransportFailure(err) {
+		// the connection to the target is lost, the target is unreachable until a
+		// subscription synced again
+		c.retry.failure(err)
+	}
+	next := s.retry.failure(err)
+	c.log.Debug("subscription failed", "subscription", s.GetName(), "error", err, "retry at", next)
+	time.AfterFunc(time.Until(next), func() {
+		c.restartSubscription(s)
+	})
+	if !disconnected {
+		return nil
+	}
+	return s
+}
+
+// restartSubscription restarts a failed subscription, unless it was stopped or
+// replaced in the meantime
+func (c *targetCollector) restartSubscription(s *Subscription) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.ctx.Err() != nil || c.subscriptions[s.GetName()] != s {
 		return
-	default:
-		pub := publisher.NewNATSPublisher(publisher.Config{
-			Address:    c.mqAddr,
-			StreamName: streamName,
-			Subjects:   []string{streamSubjects},
-			Insecure:   true,
-		}, c.log)
-
-		pub.PublishFromCh(ctx, c.updateCh)
-		goto STARTCONN
+	}
+	s.retrying = false
+	if err := c.startSubscription(s); err != nil {
+		c.log.Debug("cannot restart subscription", "subscription", s.GetName(), "error", err)
+	}
+}
+
+// deleteLastValues removes the values collected by a subscription from the last value cache
+// and the exporter, values of other subscriptions with overlapping paths are removed from
+// the last value cache as well until they are updated
+func (c *targetCollector) deleteLastValues(s *Subscription) {
+	if c.lastValueCache != nil {
+		c.lastValueCache.Delete(c.target.Config.Name, s.GetPaths())
+	}
+	if c.exporter != nil {
+		c.exporter.DeleteEntry(c.target.Config.Name, s.GetName())
+	}
+}
+
+func (c *targetCollector) publisherWorker(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			c.log.Debug("publisher stopped", "error", ctx.Err())
+			return
+		case <-c.stopCh:
+			c.log.Debug("publisher stopped")
+			return
+		case msg := <-c.queue.pop():
+			c.queue.observe()
+			c.write(ctx, msg)
+		}
+	}
+}
+
+// write writes a message to the outputs
+func (c *targetCollector) write(ctx context.Context, msg *pubsub.Msg) {
+	for _, o := range c.outputs {
+		if err := o.Write(ctx, msg); err != nil {
+			c.log.Debug("publish failed", "subject", msg.GetSubject(), "error", err)
+			publishFailures.WithLabelValues(c.target.Config.Name).Inc()
+			continue
+		}
+		msgsPublished.WithLabelValues(c.target.Config.Name).Inc()
 	}
 }
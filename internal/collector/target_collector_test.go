@@ -0,0 +1,395 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/karimra/gnmic/target"
+	"github.com/karimra/gnmic/types"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/pubsub"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestHasConnectionConfig(t *testing.T) {
+	user, other := "admin", "other"
+	running := &types.TargetConfig{
+		Name:       "default/leaf1",
+		Address:    "10.0.0.1:57400",
+		Username:   &user,
+		Timeout:    10 * time.Second,
+		BufferSize: defaultTargetReceiveBuffer,
+		RetryTimer: defaultRetryTimer,
+	}
+	c := &targetCollector{target: target.NewTarget(running)}
+
+	tests := []struct {
+		name string
+		tc   *types.TargetConfig
+		want bool
+	}{
+		{
+			name: "defaults not set",
+			tc:   &types.TargetConfig{Name: "default/leaf1", Address: "10.0.0.1:57400", Username: &user, Timeout: 10 * time.Second},
+			want: true,
+		},
+		{
+			name: "address changed",
+			tc:   &types.TargetConfig{Name: "default/leaf1", Address: "10.0.0.2:57400", Username: &user, Timeout: 10 * time.Second},
+			want: false,
+		},
+		{
+			name: "credentials changed",
+			tc:   &types.TargetConfig{Name: "default/leaf1", Address: "10.0.0.1:57400", Username: &other, Timeout: 10 * time.Second},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.HasConnectionConfig(tt.tc); got != tt.want {
+				t.Errorf("HasConnectionConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReconcileSubscriptionsInvalidEntries(t *testing.T) {
+	c := &targetCollector{
+		target:        target.NewTarget(&types.TargetConfig{Name: "default/leaf1"}),
+		subscriptions: map[string]*Subscription{},
+		retry:         newRetryState(DefaultBackoff()),
+		log:           logging.NewNopLogger(),
+		connected:     true,
+	}
+	c.ctx, c.cfn = context.WithCancel(context.Background())
+	defer c.cfn()
+
+	mc := &ygotnddpstate.Device{}
+	for _, name := range []string{"itfce", "system"} {
+		se, _ := mc.NewStateEntry(name)
+		se.Path = []string{"/" + name}
+		se.StaleAfter = strPtr("invalid")
+	}
+	err := c.ReconcileSubscriptions(mc)
+	if err == nil {
+		t.Fatalf("ReconcileSubscriptions() with invalid entries: want error")
+	}
+	// the failed entries are started again when the unchanged config is reconciled
+	if err := c.ReconcileSubscriptions(mc); err == nil {
+		t.Fatalf("ReconcileSubscriptions() again with invalid entries: want error")
+	}
+	for _, name := range []string{"itfce", "system"} {
+		s := c.GetSubscription(name)
+		if s == nil {
+			t.Fatalf("subscription %s: not kept", name)
+		}
+		if st := s.GetStatus(); !st.Rejected || st.LastError == "" {
+			t.Errorf("subscription %s: status = %+v, want rejected with error", name, st)
+		}
+	}
+}
+
+func TestTargetCollectorRetriesDial(t *testing.T) {
+	insecure := true
+	retry := newRetryState(Backoff{Initial: 10 * time.Millisecond, Max: 20 * time.Millisecond, Multiplier: 2})
+	c := NewTargetCollector(context.Background(), &types.TargetConfig{
+		Name:     "default/leaf1",
+		Address:  "127.0.0.1:1",
+		Insecure: &insecure,
+		Timeout:  50 * time.Millisecond,
+	}, WithTargetCollectorLogger(logging.NewNopLogger()), withTargetCollectorRetryState(retry))
+	defer c.Stop()
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	// the failed dial is retried with backoff without reconciling the target again
+	deadline := time.Now().Add(5 * time.Second)
+	for retry.get().Attempt < 3 {
+		if time.Now().After(deadline) {
+			t.Fatalf("dial attempts = %d, want at least 3", retry.get().Attempt)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if st := retry.get(); st.LastError == "" {
+		t.Errorf("retry state = %+v, want the dial error", st)
+	}
+}
+
+// blockingClient is a gnmi client whose subscriptions block until they are canceled
+type blockingClient struct {
+	gnmi.GNMIClient
+}
+
+func (blockingClient) Subscribe(ctx context.Context, _ ...grpc.CallOption) (gnmi.GNMI_SubscribeClient, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// streamingClient is a gnmi client whose subscribe streams block until they are canceled,
+// sent is closed when the subscribe request is sent
+type streamingClient struct {
+	gnmi.GNMIClient
+	sent chan struct{}
+}
+
+func (c streamingClient) Subscribe(ctx context.Context, _ ...grpc.CallOption) (gnmi.GNMI_SubscribeClient, error) {
+	return &blockingStream{ctx: ctx, sent: c.sent}, nil
+}
+
+type blockingStream struct {
+	gnmi.GNMI_SubscribeClient
+	ctx  context.Context
+	sent chan struct{}
+}
+
+func (s *blockingStream) Send(*gnmi.SubscribeRequest) error {
+	close(s.sent)
+	return nil
+}
+
+func (s *blockingStream) Recv() (*gnmi.SubscribeResponse, error) {
+	<-s.ctx.Done()
+	return nil, s.ctx.Err()
+}
+
+func TestSubscribeForgetsSubscription(t *testing.T) {
+	c := &targetCollector{
+		target: target.NewTarget(&types.TargetConfig{Name: "default/leaf1", RetryTimer: time.Millisecond}),
+	}
+	sent := make(chan struct{})
+	c.target.Client = streamingClient{sent: sent}
+	_, chanSubErr := c.target.ReadSubscriptions()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		c.subscribe(ctx, &gnmi.SubscribeRequest{Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{Mode: gnmi.SubscriptionList_STREAM},
+		}}, "interface@1")
+	}()
+	<-sent
+	cancel()
+	for running := true; running; {
+		select {
+		case <-chanSubErr:
+		case <-done:
+			running = false
+		}
+	}
+	if n := len(c.target.SubscribeClients); n != 0 {
+		t.Errorf("subscribe clients after the subscription returned = %d, want 0", n)
+	}
+	// a subscription that never created a subscribe client is unknown to the gnmi target
+	forgetSubscription(c.target, "interface@2")
+}
+
+// newSubscribingTargetCollector returns a connected target collector with running
+// subscriptions for the state entries, the subscriptions block until they are canceled.
+// The returned func stops the target collector.
+func newSubscribingTargetCollector(t *testing.T, backoff Backoff, names ...string) (*targetCollector, func()) {
+	tc := &types.TargetConfig{Name: "default/leaf1", BufferSize: defaultTargetReceiveBuffer, RetryTimer: time.Millisecond}
+	c := &targetCollector{
+		target:        target.NewTarget(tc),
+		queue:         newMsgQueue(tc.Name, 10, OverflowPolicyDropOldest),
+		subscriptions: map[string]*Subscription{},
+		retry:         newRetryState(backoff),
+		log:           logging.NewNopLogger(),
+		connected:     true,
+	}
+	c.target.Client = blockingClient{}
+	c.ctx, c.cfn = context.WithCancel(context.Background())
+
+	mc := &ygotnddpstate.Device{}
+	for _, name := range names {
+		se, _ := mc.NewStateEntry(name)
+		se.Path = []string{"/" + name}
+	}
+	if err := c.ReconcileSubscriptions(mc); err != nil {
+		t.Fatalf("ReconcileSubscriptions() error = %v", err)
+	}
+
+	// the canceled gnmi subscriptions report their error
+	chanSubResp, chanSubErr := c.target.ReadSubscriptions()
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-chanSubErr:
+			case <-done:
+				return
+			}
+		}
+	}()
+	return c, func() {
+		c.cfn()
+		close(done)
+		drainSubscriptions(chanSubResp, chanSubErr, 50*time.Millisecond)
+		c.queue.delete()
+	}
+}
+
+// subscriptionID returns the id of the running gnmi subscription of s
+func subscriptionID(c *targetCollector, s *Subscription) string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	return s.id
+}
+
+var syncResponse = &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true}}
+
+// TestSubscriptionRestartRace restarts a subscription while its responses are handled,
+// it is meant to be run with -race
+func TestSubscriptionRestartRace(t *testing.T) {
+	c, stop := newSubscribingTargetCollector(t, Backoff{Initial: time.Millisecond, Max: time.Millisecond, Multiplier: 1}, "interface")
+	defer stop()
+	s := c.GetSubscription("interface")
+
+	update := &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{
+		Update: []*gnmi.Update{{
+			Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface", Key: map[string]string{"name": "e1"}}, {Name: "oper-state"}}},
+			Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}},
+		}},
+	}}}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			c.handleSubscribeResponse(s, update)
+			c.handleSubscribeResponse(s, syncResponse)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			c.handleSubscriptionError(subscriptionID(c, s), errors.New("stream closed"))
+			c.restartSubscription(s)
+		}
+	}()
+	wg.Wait()
+}
+
+func TestSubscriptionRetryState(t *testing.T) {
+	// the failed subscriptions are not restarted during the test
+	c, stop := newSubscribingTargetCollector(t, Backoff{Initial: time.Minute, Multiplier: 1}, "interface", "system")
+	defer stop()
+	itfce, system := c.GetSubscription("interface"), c.GetSubscription("system")
+
+	// a failed subscription does not affect the target nor the other subscriptions
+	c.handleSubscriptionError(subscriptionID(c, itfce), status.Error(codes.NotFound, "unknown path"))
+	if got := itfce.retry.get().Attempt; got != 1 {
+		t.Errorf("subscription attempt = %d, want 1", got)
+	}
+	if got := c.retry.get().Attempt; got != 0 {
+		t.Errorf("target attempt after a subscription failure = %d, want 0", got)
+	}
+	// the sync of another subscription does not reset the backoff of the failed one
+	c.handleSubscribeResponse(system, syncResponse)
+	if got := itfce.retry.get().Attempt; got != 1 {
+		t.Errorf("subscription attempt after the sync of another one = %d, want 1", got)
+	}
+	// a lost connection makes the target unreachable
+	c.handleSubscriptionError(subscriptionID(c, system), status.Error(codes.Unavailable, "connection reset"))
+	if got := c.retry.get().Attempt; got != 1 {
+		t.Errorf("target attempt after a transport failure = %d, want 1", got)
+	}
+}
+
+// targetEvents returns the target connectivity events of the messages
+func targetEvents(msgs []*pubsub.Msg) []string {
+	events := []string{}
+	for _, msg := range msgs {
+		if strings.HasSuffix(msg.GetSubject(), "."+connectivitySubjectToken) {
+			events = append(events, msg.GetTags()[tagEvent])
+		}
+	}
+	return events
+}
+
+// queuedMsgs returns the messages waiting in the queue of the target collector
+func queuedMsgs(c *targetCollector) []*pubsub.Msg {
+	msgs := []*pubsub.Msg{}
+	for {
+		select {
+		case msg := <-c.queue.pop():
+			msgs = append(msgs, msg)
+		default:
+			return msgs
+		}
+	}
+}
+
+func TestTargetConnectivityEvents(t *testing.T) {
+	c, stop := newSubscribingTargetCollector(t, Backoff{Initial: time.Minute, Multiplier: 1}, "interface")
+	defer stop()
+	s := c.GetSubscription("interface")
+	update := &gnmi.SubscribeResponse{Response: &gnmi.SubscribeResponse_Update{Update: &gnmi.Notification{}}}
+
+	// the target is connected, loses the connection and resyncs
+	c.publishTargetConnectivity(ConnectivityConnected, nil)
+	c.handleSubscribeResponse(s, update)
+	c.handleSubscribeResponse(s, syncResponse)
+	c.handleSubscriptionError(subscriptionID(c, s), status.Error(codes.Unavailable, "connection reset"))
+	c.restartSubscription(s)
+	c.handleSubscribeResponse(s, update)
+	c.handleSubscribeResponse(s, syncResponse)
+
+	want := []string{"connected", "disconnected", "connected", "resynced"}
+	if got := targetEvents(queuedMsgs(c)); !reflect.DeepEqual(got, want) {
+		t.Errorf("target events = %v, want %v", got, want)
+	}
+
+	// the stopped target collector publishes the disconnected event to the outputs
+	out := NewMemoryOutput(10)
+	c.outputs = []Output{out}
+	c.publishTargetStopped()
+	if got := targetEvents(out.Messages()); !reflect.DeepEqual(got, []string{"disconnected"}) {
+		t.Errorf("target events after stop = %v, want [disconnected]", got)
+	}
+}
+
+func TestTargetConnectivityDialFailure(t *testing.T) {
+	insecure := true
+	retry := newRetryState(Backoff{Initial: 10 * time.Millisecond, Max: 20 * time.Millisecond, Multiplier: 2})
+	out := NewMemoryOutput(10)
+	c := NewTargetCollector(context.Background(), &types.TargetConfig{
+		Name:     "default/leaf-unreachable",
+		Address:  "127.0.0.1:1",
+		Insecure: &insecure,
+		Timeout:  50 * time.Millisecond,
+	}, WithTargetCollectorLogger(logging.NewNopLogger()), WithTargetCollectorOutputs([]Output{out}),
+		withTargetCollectorRetryState(retry))
+	defer deleteTargetMetrics("default/leaf-unreachable", nil)
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	// a target that is unreachable at start is reported disconnected once
+	deadline := time.Now().Add(5 * time.Second)
+	for retry.get().Attempt < 3 || len(out.Messages()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("dial attempts = %d and %d messages, want at least 3 attempts and a message", retry.get().Attempt, len(out.Messages()))
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	c.Stop()
+	msgs := out.Messages()
+	if got := targetEvents(msgs); !reflect.DeepEqual(got, []string{"disconnected"}) {
+		t.Fatalf("target events = %v, want [disconnected]", got)
+	}
+	if msgs[0].GetTags()[tagError] == "" {
+		t.Errorf("disconnected event without the dial error: %+v", msgs[0])
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 
@@ -35,9 +36,9 @@ import (
 	"github.com/yndd/cache/pkg/model"
 	"github.com/yndd/cache/pkg/origin"
 
-	//nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
 	pkgmetav1 "github.com/yndd/ndd-core/apis/pkg/meta/v1"
 	pkgv1 "github.com/yndd/ndd-core/apis/pkg/v1"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
 	"github.com/yndd/ndd-runtime/pkg/event"
 	"github.com/yndd/ndd-runtime/pkg/logging"
 	"github.com/yndd/ndd-runtime/pkg/resource"
@@ -47,12 +48,14 @@ import (
 	"github.com/yndd/observability-runtime/pkg/reconciler/managed"
 	"github.com/yndd/registrator/registrator"
 	statev1alpha1 "github.com/yndd/state/apis/state/v1alpha1"
+	"github.com/yndd/state/pkg/entrystatus"
 	"github.com/yndd/state/pkg/ygotnddpstate"
 	targetv1 "github.com/yndd/target/apis/target/v1"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
 	//corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -69,14 +72,14 @@ func Setup(mgr ctrl.Manager, nddopts *shared.NddControllerOptions) error {
 		StructRootType:  reflect.TypeOf((*ygotnddpstate.Device)(nil)),
 		SchemaTreeRoot:  ygotnddpstate.SchemaTree["Device"],
 		JsonUnmarshaler: ygotnddpstate.Unmarshal,
-		//EnumData:        ygotnddpstate.ΛEnum,
+		EnumData:        ygotnddpstate.ΛEnum,
 	}
 
 	m := &model.Model{
 		StructRootType:  reflect.TypeOf((*ygotnddpstate.YnddState_StateEntry)(nil)),
 		SchemaTreeRoot:  ygotnddpstate.SchemaTree["NddpState_StateEntry"],
 		JsonUnmarshaler: ygotnddpstate.Unmarshal,
-		//EnumData:        ygotnddpstate.ΛEnum,
+		EnumData:        ygotnddpstate.ΛEnum,
 	}
 
 	r := managed.NewReconciler(mgr,
@@ -147,11 +150,6 @@ func (c *connectorDevice) Connect(ctx context.Context, mg resource.Managed) (man
 		return nil, errors.Wrap(err, errGetTarget)
 	}
 
-	// TODO Target status should be updated
-	//if t.GetCondition(nddv1.ConditionKindReady).Status != corev1.ConditionTrue {
-	//	return nil, errors.New(targetNotConfigured)
-	//}
-
 	//address := "state-worker-controller-grpc-svc.ndd-system.svc.cluster.local"
 	workerservice := strings.Join([]string{os.Getenv("COMPOSITE_PROVIDER_NAME"), "worker-controller-grpc-svc"}, "-")
 	address := fmt.Sprintf("%s.%s.%s.%s.%s:%d", workerservice, os.Getenv("POD_NAMESPACE"), "svc", "cluster", "local", pkgmetav1.GnmiServerPort)
@@ -173,17 +171,33 @@ func (c *connectorDevice) Connect(ctx context.Context, mg resource.Managed) (man
 		Address:    address,
 		Timeout:    10 * time.Second,
 		SkipVerify: utils.BoolPtr(true),
-		TLSCA:      utils.StringPtr(""), //TODO TLS
-		TLSCert:    utils.StringPtr(""), //TODO TLS
+		TLSCA:      utils.StringPtr(""),
+		TLSCert:    utils.StringPtr(""),
 		TLSKey:     utils.StringPtr(""),
 		Gzip:       utils.BoolPtr(false),
 	}
+	// verify the certificate of the worker with the ca of the grpc certificate of the
+	// reconciler and present the grpc certificate of the reconciler to the worker, the
+	// certificates of the reconciler and the worker are issued by the same ca
+	if certDir := os.Getenv("GRPC_CERT_DIR"); certDir != "" {
+		cfg.SkipVerify = utils.BoolPtr(false)
+		cfg.TLSCA = utils.StringPtr(filepath.Join(certDir, "ca.crt"))
+		cfg.TLSCert = utils.StringPtr(filepath.Join(certDir, "tls.crt"))
+		cfg.TLSKey = utils.StringPtr(filepath.Join(certDir, "tls.key"))
+	}
 
 	cl := target.NewTarget(cfg)
 	if err := cl.CreateGNMIClient(ctx); err != nil {
 		return nil, errors.Wrap(err, errNewClient)
 	}
 
+	// the target is found when the worker collects its state, the reachability of the
+	// target as seen by the worker is reflected in the TargetReachable condition
+	if err := observeTargetStatus(ctx, cl, cr, log); err != nil {
+		cl.Close()
+		return nil, err
+	}
+
 	tns := []string{t.GetName()}
 
 	return &externalDevice{client: cl, targets: tns, log: log, m: c.m, fm: c.fm}, nil
@@ -280,6 +294,9 @@ func (e *externalDevice) Observe(ctx context.Context, mg resource.Managed) (mana
 
 	log.Debug("Observing ...", "cacheStateEntry", cacheStateEntry)
 
+	// reflect the status of the collection of the state entry in the conditions
+	e.observeEntryStatus(ctx, mg, crTarget, *stateEntry.Name)
+
 	// check if the cacheData is aligned with the crSpecData
 	deletes, updates, err := e.diff(mg, cacheStateEntry)
 	if err != nil {
@@ -292,6 +309,105 @@ func (e *externalDevice) Observe(ctx context.Context, mg resource.Managed) (mana
 	}, nil
 }
 
+// observeTargetStatus gets the status of the target from the worker and sets the
+// TargetFound and TargetReachable conditions, a target the worker does not know is
+// not found and reported as not configured
+func observeTargetStatus(ctx context.Context, cl *target.Target, cr *statev1alpha1.State, log logging.Logger) error {
+	crTarget := strings.Join([]string{cr.GetNamespace(), cr.GetTargetReference().Name}, "/")
+	resp, err := cl.Get(ctx, &gnmi.GetRequest{
+		Prefix:   &gnmi.Path{Origin: entrystatus.Origin, Target: crTarget},
+		Path:     []*gnmi.Path{entrystatus.TargetPath()},
+		Encoding: gnmi.Encoding_JSON,
+	})
+	if err != nil {
+		log.Debug("Observing target status ...", "error", err)
+		if er, ok := status.FromError(err); ok && er.Code() == codes.NotFound {
+			cr.SetConditions(nddv1.TargetNotFound())
+			return errors.New(targetNotConfigured)
+		}
+		// the reachability is unknown, e.g. the worker is not ready yet
+		return nil
+	}
+	cr.SetConditions(nddv1.TargetFound())
+	if len(resp.GetNotification()) == 0 || len(resp.GetNotification()[0].GetUpdate()) == 0 {
+		return nil
+	}
+	ts, err := entrystatus.TargetFromUpdate(resp.GetNotification()[0].GetUpdate()[0])
+	if err != nil {
+		log.Debug("Observing target status ...", "error", err)
+		return nil
+	}
+	log.Debug("Observing target status ...", "status", ts)
+	switch {
+	case ts.Reachable:
+		cr.SetConditions(statev1alpha1.TargetReachable())
+	case ts.Pending:
+		cr.SetConditions(statev1alpha1.TargetReachabilityPending("the worker is connecting to the target"))
+	default:
+		cr.SetConditions(statev1alpha1.TargetUnreachable(ts.LastError))
+	}
+	return nil
+}
+
+// observeEntryStatus gets the status of the collection of the state entry from the worker
+// and sets the Subscribed, DataSynced and Degraded conditions accordingly, state entries
+// the target does not support fully are reported as rejected or downgraded
+func (e *externalDevice) observeEntryStatus(ctx context.Context, mg resource.Managed, crTarget, name string) {
+	log := e.log.WithValues("Resource", mg.GetName())
+	cr, ok := mg.(*statev1alpha1.State)
+	if !ok {
+		return
+	}
+
+	resp, err := e.client.Get(ctx, &gnmi.GetRequest{
+		Prefix:   &gnmi.Path{Origin: entrystatus.Origin, Target: crTarget},
+		Path:     []*gnmi.Path{entrystatus.Path(name)},
+		Encoding: gnmi.Encoding_JSON,
+	})
+	if err != nil {
+		log.Debug("Observing entry status ...", "error", err)
+		return
+	}
+	if len(resp.GetNotification()) == 0 || len(resp.GetNotification()[0].GetUpdate()) == 0 {
+		return
+	}
+	es, err := entrystatus.FromUpdate(resp.GetNotification()[0].GetUpdate()[0])
+	if err != nil {
+		log.Debug("Observing entry status ...", "error", err)
+		return
+	}
+	log.Debug("Observing entry status ...", "status", es)
+
+	switch {
+	case es.Rejected:
+		cr.SetConditions(statev1alpha1.SubscriptionRejected(es.Message))
+	case es.Subscribed:
+		cr.SetConditions(statev1alpha1.Subscribed())
+	default:
+		cr.SetConditions(statev1alpha1.NotSubscribed())
+	}
+	if es.Synced {
+		cr.SetConditions(statev1alpha1.DataSynced())
+	} else {
+		cr.SetConditions(statev1alpha1.DataNotSynced())
+	}
+	switch {
+	case es.Rejected:
+		cr.SetConditions(statev1alpha1.Degraded(es.Message))
+	case es.LastError != "" && !es.Synced:
+		// an error is reported until the subscription synced again
+		cr.SetConditions(statev1alpha1.Degraded(es.LastError))
+	case es.Message != "":
+		cr.SetConditions(statev1alpha1.Downgraded(es.Message))
+	default:
+		cr.SetConditions(statev1alpha1.NotDegraded())
+	}
+	if !es.LastUpdate.IsZero() {
+		t := metav1.NewTime(es.LastUpdate)
+		cr.Status.LastUpdate = &t
+	}
+}
+
 func (e *externalDevice) Create(ctx context.Context, mg resource.Managed) error {
 	crTarget := strings.Join([]string{mg.GetNamespace(), mg.GetTargetReference().Name}, "/")
 	log := e.log.WithValues("Resource", mg.GetName(), "crTarget", crTarget)
@@ -0,0 +1,184 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lastvalue
+
+import (
+	"context"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	gnmicache "github.com/openconfig/gnmi/cache"
+	"github.com/openconfig/gnmi/ctree"
+	"github.com/openconfig/gnmi/match"
+	"github.com/openconfig/gnmi/metadata"
+	"github.com/openconfig/gnmi/path"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Origin is the gnmi origin the last values are served on
+const Origin = "telemetry"
+
+// Option can be used to manipulate the Cache.
+type Option func(Cache)
+
+// Cache keeps the last collected value of every leaf per target
+type Cache interface {
+	// add a logger to the Cache
+	WithLogger(log logging.Logger)
+	// AddTarget reserves space for the values of a target
+	AddTarget(target string)
+	// RemoveTarget removes the target and all its values
+	RemoveTarget(target string)
+	// Update stores the updates and removes the deletes of a notification
+	Update(target string, n *gnmi.Notification)
+	// Delete removes the values at or below the paths, the paths can contain wildcards
+	Delete(target string, paths []*gnmi.Path)
+	// Get returns the last values of the paths in the request
+	Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error)
+	// Subscribe streams the values of the paths in the request and their changes
+	Subscribe(req *gnmi.SubscribeRequest, stream gnmi.GNMI_SubscribeServer) error
+	// GetGnmiCache returns the underlying gnmi cache
+	GetGnmiCache() *gnmicache.Cache
+}
+
+// WithLogger specifies how the cache logs messages.
+func WithLogger(log logging.Logger) Option {
+	return func(c Cache) {
+		c.WithLogger(log)
+	}
+}
+
+type lastValueCache struct {
+	c *gnmicache.Cache
+	// northbound subscriptions matched against the updates of the cache
+	m   *match.Match
+	log logging.Logger
+}
+
+// New creates a new last value Cache
+func New(opts ...Option) Cache {
+	c := &lastValueCache{
+		c: gnmicache.New(nil),
+		m: match.New(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.c.SetClient(c.update)
+	return c
+}
+
+func (c *lastValueCache) WithLogger(log logging.Logger) {
+	c.log = log
+}
+
+func (c *lastValueCache) GetGnmiCache() *gnmicache.Cache {
+	return c.c
+}
+
+func (c *lastValueCache) AddTarget(target string) {
+	if !c.c.HasTarget(target) {
+		c.c.Add(target)
+	}
+}
+
+func (c *lastValueCache) RemoveTarget(target string) {
+	c.c.Remove(target)
+}
+
+func (c *lastValueCache) Update(target string, n *gnmi.Notification) {
+	// the notification is stored in the cache, hence it is cloned to set the target
+	n = proto.Clone(n).(*gnmi.Notification)
+	if n.Prefix == nil {
+		n.Prefix = &gnmi.Path{}
+	}
+	n.Prefix.Target = target
+	// the cache indexes the origin as a path element, the values are served on their own origin
+	n.Prefix.Origin = ""
+	if err := c.c.GnmiUpdate(n); err != nil {
+		// stale and duplicate updates are rejected by the cache
+		c.log.Debug("cannot update last value cache", "target", target, "error", err)
+	}
+}
+
+func (c *lastValueCache) Delete(target string, paths []*gnmi.Path) {
+	if len(paths) == 0 {
+		return
+	}
+	n := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Prefix:    &gnmi.Path{Target: target},
+		Delete:    paths,
+	}
+	if err := c.c.GnmiUpdate(n); err != nil {
+		c.log.Debug("cannot delete from last value cache", "target", target, "error", err)
+	}
+}
+
+// Get returns a notification per leaf that matches a path of the request, the paths
+// can contain wildcards for the elements and the key values. A key that is not
+// specified is not treated as a wildcard, use [key=*] instead.
+func (c *lastValueCache) Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	prefix := req.GetPrefix()
+	target := prefix.GetTarget()
+	log := c.log.WithValues("origin", prefix.GetOrigin(), "target", target)
+	log.Debug("Get...", "path", req.GetPath())
+
+	if target == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "no target specified")
+	}
+	if target != "*" && !c.c.HasTarget(target) {
+		return nil, status.Errorf(codes.NotFound, "target %s not found", target)
+	}
+
+	paths := req.GetPath()
+	if len(paths) == 0 {
+		// the prefix is the path
+		paths = []*gnmi.Path{{}}
+	}
+	ns := make([]*gnmi.Notification, 0)
+	for _, p := range paths {
+		query := path.ToStrings(&gnmi.Path{Elem: append(append([]*gnmi.PathElem{}, prefix.GetElem()...), p.GetElem()...)}, false)
+		found := false
+		if err := c.c.Query(target, query, func(p []string, _ *ctree.Leaf, v interface{}) error {
+			if isMetadata(p) {
+				return nil
+			}
+			if n, ok := v.(*gnmi.Notification); ok {
+				ns = append(ns, n)
+				found = true
+			}
+			return nil
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "%v", err)
+		}
+		if !found {
+			return nil, status.Errorf(codes.NotFound, "no value found for path %v", p)
+		}
+	}
+	return &gnmi.GetResponse{
+		Notification: ns,
+	}, nil
+}
+
+// isMetadata returns true for the paths of the cache metadata, which is not collected state
+func isMetadata(p []string) bool {
+	return len(p) > 0 && p[0] == metadata.Root
+}
@@ -0,0 +1,136 @@
+package lastvalue
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func itfcePath(name, leaf string) *gnmi.Path {
+	return &gnmi.Path{
+		Elem: []*gnmi.PathElem{
+			{Name: "interface", Key: map[string]string{"name": name}},
+			{Name: leaf},
+		},
+	}
+}
+
+func stringUpdate(p *gnmi.Path, v string) *gnmi.Update {
+	return &gnmi.Update{Path: p, Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: v}}}
+}
+
+func Test_Get(t *testing.T) {
+	c := New(WithLogger(logging.NewNopLogger()))
+	c.AddTarget("leaf1")
+	c.Update("leaf1", &gnmi.Notification{
+		Timestamp: 1,
+		Prefix:    &gnmi.Path{Origin: "openconfig"},
+		Update: []*gnmi.Update{
+			stringUpdate(itfcePath("ethernet-1/1", "oper-state"), "up"),
+			stringUpdate(itfcePath("ethernet-1/2", "oper-state"), "down"),
+		},
+	})
+
+	tests := []struct {
+		name string
+		path *gnmi.Path
+		want int
+		code codes.Code
+	}{
+		{
+			name: "exact",
+			path: itfcePath("ethernet-1/1", "oper-state"),
+			want: 1,
+		},
+		{
+			name: "key_wildcard",
+			path: itfcePath("*", "oper-state"),
+			want: 2,
+		},
+		{
+			name: "subtree",
+			path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "interface"}}},
+			want: 2,
+		},
+		{
+			name: "not_found",
+			path: itfcePath("ethernet-1/3", "oper-state"),
+			code: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsp, err := c.Get(context.Background(), &gnmi.GetRequest{
+				Prefix: &gnmi.Path{Origin: Origin, Target: "leaf1"},
+				Path:   []*gnmi.Path{tt.path},
+			})
+			if status.Code(err) != tt.code {
+				t.Fatalf("Get() error = %v, want code %v", err, tt.code)
+			}
+			if got := len(rsp.GetNotification()); got != tt.want {
+				t.Errorf("Get() got %d notifications, want %d", got, tt.want)
+			}
+		})
+	}
+
+	// the values of a deleted path are no longer returned
+	c.Delete("leaf1", []*gnmi.Path{itfcePath("*", "oper-state")})
+	if _, err := c.Get(context.Background(), &gnmi.GetRequest{
+		Prefix: &gnmi.Path{Origin: Origin, Target: "leaf1"},
+		Path:   []*gnmi.Path{itfcePath("*", "oper-state")},
+	}); status.Code(err) != codes.NotFound {
+		t.Errorf("Get() after Delete error = %v, want code %v", err, codes.NotFound)
+	}
+}
+
+type fakeSubscribeStream struct {
+	gnmi.GNMI_SubscribeServer
+	ctx context.Context
+	rsp []*gnmi.SubscribeResponse
+}
+
+func (s *fakeSubscribeStream) Context() context.Context { return s.ctx }
+
+func (s *fakeSubscribeStream) Send(rsp *gnmi.SubscribeResponse) error {
+	s.rsp = append(s.rsp, rsp)
+	return nil
+}
+
+func Test_SubscribeOnce(t *testing.T) {
+	c := New(WithLogger(logging.NewNopLogger()))
+	c.AddTarget("leaf1")
+	c.Update("leaf1", &gnmi.Notification{
+		Timestamp: 1,
+		Update: []*gnmi.Update{
+			stringUpdate(itfcePath("ethernet-1/1", "oper-state"), "up"),
+			stringUpdate(itfcePath("ethernet-1/1", "admin-state"), "enable"),
+			stringUpdate(itfcePath("ethernet-1/2", "oper-state"), "down"),
+		},
+	})
+
+	stream := &fakeSubscribeStream{ctx: context.Background()}
+	if err := c.Subscribe(&gnmi.SubscribeRequest{
+		Request: &gnmi.SubscribeRequest_Subscribe{
+			Subscribe: &gnmi.SubscriptionList{
+				Prefix: &gnmi.Path{Target: "leaf1"},
+				Mode:   gnmi.SubscriptionList_ONCE,
+				Subscription: []*gnmi.Subscription{
+					{Path: itfcePath("*", "oper-state")},
+				},
+			},
+		},
+	}, stream); err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	// 2 updates followed by a sync response
+	if len(stream.rsp) != 3 {
+		t.Fatalf("Subscribe() got %d responses, want 3", len(stream.rsp))
+	}
+	if !stream.rsp[2].GetSyncResponse() {
+		t.Errorf("Subscribe() last response is not a sync response: %v", stream.rsp[2])
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package lastvalue
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/openconfig/gnmi/coalesce"
+	"github.com/openconfig/gnmi/ctree"
+	"github.com/openconfig/gnmi/path"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/gnmi/subscribe"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// defaultSampleInterval is used for sample subscriptions without a sample interval
+	defaultSampleInterval = 10 * time.Second
+	// minSampleInterval is the lowest sample and heartbeat interval supported
+	minSampleInterval = time.Second
+)
+
+// syncMarker is queued after the initial values of a subscription to send a sync response
+type syncMarker struct{}
+
+// subscribeClient is a queue of leaves to be sent to a northbound subscribe client
+type subscribeClient struct {
+	q *coalesce.Queue
+}
+
+// Update queues a leaf that changed, pending updates of the same leaf are coalesced
+func (s *subscribeClient) Update(v interface{}) {
+	s.q.Insert(v)
+}
+
+// update is called by the gnmi cache for every accepted update and delete
+func (c *lastValueCache) update(l *ctree.Leaf) {
+	n, ok := l.Value().(*gnmi.Notification)
+	if !ok {
+		return
+	}
+	subscribe.UpdateNotification(c.m, l, n, path.ToStrings(n.GetPrefix(), true))
+}
+
+// Subscribe streams the collected values to a northbound client. The paths of the
+// subscriptions can contain wildcards. ONCE, POLL and STREAM subscription lists are
+// supported, in STREAM mode ON_CHANGE and TARGET_DEFINED subscriptions stream every
+// change and SAMPLE subscriptions send the values every sample interval.
+func (c *lastValueCache) Subscribe(req *gnmi.SubscribeRequest, stream gnmi.GNMI_SubscribeServer) error {
+	sl := req.GetSubscribe()
+	switch {
+	case sl == nil:
+		return status.Errorf(codes.InvalidArgument, "request must contain a subscription list")
+	case sl.GetPrefix().GetTarget() == "":
+		return status.Errorf(codes.InvalidArgument, "missing target")
+	}
+	target := sl.GetPrefix().GetTarget()
+	if target != "*" && !c.c.HasTarget(target) {
+		return status.Errorf(codes.NotFound, "target %s not found", target)
+	}
+	log := c.log.WithValues("target", target, "mode", sl.GetMode())
+	log.Debug("Subscribe...", "subscriptions", sl.GetSubscription())
+
+	queries := make([][]string, 0, len(sl.GetSubscription()))
+	for _, s := range sl.GetSubscription() {
+		q, err := path.CompletePath(sl.GetPrefix(), s.GetPath())
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "%v", err)
+		}
+		queries = append(queries, q)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	client := &subscribeClient{q: coalesce.NewQueue()}
+	defer client.q.Close()
+	errCh := make(chan error, 1)
+
+	switch sl.GetMode() {
+	case gnmi.SubscriptionList_ONCE:
+		c.queryAll(target, queries, client)
+		client.q.Insert(syncMarker{})
+		// the queue is closed after the last value is sent
+		go func() {
+			errCh <- c.send(ctx, stream, client, true)
+		}()
+		return <-errCh
+	case gnmi.SubscriptionList_POLL:
+		c.queryAll(target, queries, client)
+		client.q.Insert(syncMarker{})
+		go c.poll(stream, target, queries, client, errCh)
+	case gnmi.SubscriptionList_STREAM:
+		for i, s := range sl.GetSubscription() {
+			query := append([]string{target}, queries[i]...)
+			switch s.GetMode() {
+			case gnmi.SubscriptionMode_SAMPLE:
+				go c.sample(ctx, target, queries[i], sampleInterval(s.GetSampleInterval()), s.GetSuppressRedundant(), client)
+			default:
+				// changes are streamed as they are received from the device
+				remove := c.m.AddQuery(query, client)
+				defer remove()
+				if hb := s.GetHeartbeatInterval(); hb > 0 {
+					go c.sample(ctx, target, queries[i], sampleInterval(hb), false, client)
+				}
+			}
+		}
+		if !sl.GetUpdatesOnly() {
+			c.queryAll(target, queries, client)
+		}
+		client.q.Insert(syncMarker{})
+	default:
+		return status.Errorf(codes.InvalidArgument, "subscription list mode %v not supported", sl.GetMode())
+	}
+
+	go func() {
+		errCh <- c.send(ctx, stream, client, false)
+	}()
+	err := <-errCh
+	log.Debug("Subscribe stopped", "error", err)
+	return err
+}
+
+// queryAll queues the current values of the queries
+func (c *lastValueCache) queryAll(target string, queries [][]string, client *subscribeClient) {
+	for _, q := range queries {
+		c.query(target, q, func(l *ctree.Leaf, _ *gnmi.Notification) {
+			client.q.Insert(l)
+		})
+	}
+}
+
+// query calls fn for every leaf with collected state that matches the query
+func (c *lastValueCache) query(target string, query []string, fn func(l *ctree.Leaf, n *gnmi.Notification)) {
+	if err := c.c.Query(target, query, func(p []string, l *ctree.Leaf, v interface{}) error {
+		if n, ok := v.(*gnmi.Notification); ok && !isMetadata(p) {
+			fn(l, n)
+		}
+		return nil
+	}); err != nil {
+		c.log.Debug("cannot query last value cache", "target", target, "error", err)
+	}
+}
+
+// poll queues the current values for every poll request of the client
+func (c *lastValueCache) poll(stream gnmi.GNMI_SubscribeServer, target string, queries [][]string, client *subscribeClient, errCh chan error) {
+	for {
+		req, err := stream.Recv()
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			errCh <- err
+			return
+		}
+		if req.GetPoll() == nil {
+			errCh <- status.Errorf(codes.InvalidArgument, "expected a poll request")
+			return
+		}
+		c.queryAll(target, queries, client)
+		client.q.Insert(syncMarker{})
+	}
+}
+
+// sample queues the current values of the query every interval, if suppressRedundant
+// is set only the values that changed since the previous sample are queued
+func (c *lastValueCache) sample(ctx context.Context, target string, query []string, interval time.Duration, suppressRedundant bool, client *subscribeClient) {
+	sent := map[*ctree.Leaf]int64{}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.query(target, query, func(l *ctree.Leaf, n *gnmi.Notification) {
+				if suppressRedundant && sent[l] == n.GetTimestamp() {
+					return
+				}
+				sent[l] = n.GetTimestamp()
+				client.q.Insert(l)
+			})
+		}
+	}
+}
+
+// send sends the queued leaves to the client until the context is done, if once
+// is set it returns after the first sync response
+func (c *lastValueCache) send(ctx context.Context, stream gnmi.GNMI_SubscribeServer, client *subscribeClient, once bool) error {
+	for {
+		item, dup, err := client.q.Next(ctx)
+		if err != nil {
+			if coalesce.IsClosedQueue(err) || ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		switch v := item.(type) {
+		case syncMarker:
+			if err := stream.Send(&gnmi.SubscribeResponse{
+				Response: &gnmi.SubscribeResponse_SyncResponse{SyncResponse: true},
+			}); err != nil {
+				return err
+			}
+			if once {
+				return nil
+			}
+		case *ctree.Leaf:
+			rsp, err := subscribe.MakeSubscribeResponse(v.Value(), dup)
+			if err != nil {
+				return err
+			}
+			if err := stream.Send(rsp); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func sampleInterval(ns uint64) time.Duration {
+	d := time.Duration(ns)
+	switch {
+	case d == 0:
+		return defaultSampleInterval
+	case d < minSampleInterval:
+		return minSampleInterval
+	}
+	return d
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promexporter
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/state/pkg/value"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+const (
+	// labelTarget is the label with the target name added to every metric
+	labelTarget = "target"
+	// metricsPath is the http path the metrics are served on
+	metricsPath = "/metrics"
+
+	defaultShutdownTimeout = 5 * time.Second
+
+	// errors
+	errServeMetrics = "cannot serve prometheus metrics"
+)
+
+// Option can be used to manipulate the Exporter.
+type Option func(Exporter)
+
+// Exporter exposes the numeric leaves collected from the targets as prometheus metrics
+type Exporter interface {
+	// add a logger to the Exporter
+	WithLogger(log logging.Logger)
+	// Update records the numeric updates and removes the deletes of a notification
+	// collected for a state entry
+	Update(target string, se *ygotnddpstate.YnddState_StateEntry, n *gnmi.Notification)
+	// DeleteEntry removes the metrics of a state entry
+	DeleteEntry(target, entry string)
+	// DeleteTarget removes the metrics of a target
+	DeleteTarget(target string)
+	// Start serves the metrics on the address until the context is done
+	Start(ctx context.Context, address string) error
+}
+
+// WithLogger specifies how the exporter logs messages.
+func WithLogger(log logging.Logger) Option {
+	return func(e Exporter) {
+		e.WithLogger(log)
+	}
+}
+
+// sample is the last value of a numeric leaf
+type sample struct {
+	// path of the leaf, used to match deletes
+	path  string
+	name  string
+	desc  *prometheus.Desc
+	value float64
+	// label names and values in the order of the label names of the desc
+	labelNames  []string
+	labelValues []string
+}
+
+// id returns the identity of the series of the sample
+func (s *sample) id() string {
+	return s.name + "\xff" + strings.Join(s.labelValues, "\xff")
+}
+
+type exporter struct {
+	m sync.RWMutex
+	// samples indexed by target, state entry name and series
+	samples map[string]map[string]map[string]*sample
+	log     logging.Logger
+}
+
+// New creates a new prometheus Exporter
+func New(opts ...Option) Exporter {
+	e := &exporter{
+		samples: map[string]map[string]map[string]*sample{},
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+func (e *exporter) WithLogger(log logging.Logger) {
+	e.log = log
+}
+
+func (e *exporter) Start(ctx context.Context, address string) error {
+	reg := prometheus.NewRegistry()
+	if err := reg.Register(e); err != nil {
+		return errors.Wrap(err, errServeMetrics)
+	}
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	srv := &http.Server{Addr: address, Handler: mux}
+
+	go func() {
+		<-ctx.Done()
+		sctx, cancel := context.WithTimeout(context.Background(), defaultShutdownTimeout)
+		defer cancel()
+		srv.Shutdown(sctx)
+	}()
+	go func() {
+		e.log.Debug("serving prometheus metrics", "address", address)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			e.log.Debug(errServeMetrics, "address", address, "error", err)
+		}
+	}()
+	return nil
+}
+
+// Describe implements prometheus.Collector, the exporter is an unchecked
+// collector since the metrics depend on the collected state
+func (e *exporter) Describe(ch chan<- *prometheus.Desc) {}
+
+// Collect implements prometheus.Collector. The state entries of a target may cover the
+// same paths, a series is collected once and the samples of a metric name that is used
+// with different label names are dropped, the first sample in the order of the target,
+// the state entry and the series determines the labels of a metric name.
+func (e *exporter) Collect(ch chan<- prometheus.Metric) {
+	e.m.RLock()
+	defer e.m.RUnlock()
+	// the first desc of every metric name, the help of a metric name must be consistent
+	descs := map[string]*sample{}
+	collected := map[string]struct{}{}
+	targets := make([]string, 0, len(e.samples))
+	for target := range e.samples {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+	for _, target := range targets {
+		entries := e.samples[target]
+		entryNames := make([]string, 0, len(entries))
+		for entry := range entries {
+			entryNames = append(entryNames, entry)
+		}
+		sort.Strings(entryNames)
+		for _, entry := range entryNames {
+			series := entries[entry]
+			keys := make([]string, 0, len(series))
+			for k := range series {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				s := series[k]
+				first, ok := descs[s.name]
+				if !ok {
+					first = s
+					descs[s.name] = s
+				}
+				if !equal(first.labelNames, s.labelNames) {
+					e.log.Debug("metric name used with different labels", "name", s.name, "entry", entry, "path", s.path,
+						"labels", s.labelNames, "want labels", first.labelNames)
+					continue
+				}
+				if _, ok := collected[s.id()]; ok {
+					// the series is collected by another state entry
+					continue
+				}
+				collected[s.id()] = struct{}{}
+				m, err := prometheus.NewConstMetric(first.desc, prometheus.GaugeValue, s.value, s.labelValues...)
+				if err != nil {
+					e.log.Debug("cannot create metric", "path", s.path, "error", err)
+					continue
+				}
+				ch <- m
+			}
+		}
+	}
+}
+
+func (e *exporter) Update(target string, se *ygotnddpstate.YnddState_StateEntry, n *gnmi.Notification) {
+	if se.Prometheus != nil && se.Prometheus.Enabled != nil && !*se.Prometheus.Enabled {
+		return
+	}
+	entry := *se.Name
+
+	e.m.Lock()
+	defer e.m.Unlock()
+	if _, ok := e.samples[target]; !ok {
+		e.samples[target] = map[string]map[string]*sample{}
+	}
+	series, ok := e.samples[target][entry]
+	if !ok {
+		series = map[string]*sample{}
+		e.samples[target][entry] = series
+	}
+
+	for _, del := range n.GetDelete() {
+		p := pathString(joinPath(n.GetPrefix(), del))
+		for k, s := range series {
+			if s.path == p || strings.HasPrefix(s.path, p+"/") {
+				delete(series, k)
+			}
+		}
+	}
+	for _, upd := range n.GetUpdate() {
+		v, ok := toFloat(upd.GetVal())
+		if !ok {
+			continue
+		}
+		p := joinPath(n.GetPrefix(), upd.GetPath())
+		s := newSample(target, se.Prometheus, p)
+		s.value = v
+		series[s.id()] = s
+	}
+}
+
+func (e *exporter) DeleteEntry(target, entry string) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	if entries, ok := e.samples[target]; ok {
+		delete(entries, entry)
+	}
+}
+
+func (e *exporter) DeleteTarget(target string) {
+	e.m.Lock()
+	defer e.m.Unlock()
+	delete(e.samples, target)
+}
+
+// newSample returns the sample of a leaf, the metric name is derived from the
+// element names of the path and the labels from the keys of the path
+func newSample(target string, cfg *ygotnddpstate.YnddState_StateEntry_Prometheus, p []*gnmi.PathElem) *sample {
+	names := make([]string, 0, len(p))
+	for _, pe := range p {
+		names = append(names, pe.GetName())
+	}
+	name := strings.Join(names, "_")
+	if cfg != nil && cfg.MetricName != nil && *cfg.MetricName != "" && len(names) > 0 {
+		name = *cfg.MetricName + "_" + names[len(names)-1]
+	}
+
+	labelNames := []string{labelTarget}
+	labelValues := []string{target}
+	for _, pe := range p {
+		keys := make([]string, 0, len(pe.GetKey()))
+		for k := range pe.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if cfg != nil && len(cfg.LabelKey) > 0 && !contains(cfg.LabelKey, k) {
+				continue
+			}
+			ln := sanitize(k)
+			if contains(labelNames, ln) {
+				// the same key name is used at different levels of the path
+				ln = sanitize(pe.GetName() + "_" + k)
+			}
+			labelNames = append(labelNames, ln)
+			labelValues = append(labelValues, pe.GetKey()[k])
+		}
+	}
+	return &sample{
+		path:        pathString(p),
+		name:        sanitize(name),
+		desc:        prometheus.NewDesc(sanitize(name), "collected state of /"+strings.Join(names, "/"), labelNames, nil),
+		labelNames:  labelNames,
+		labelValues: labelValues,
+	}
+}
+
+// toFloat returns the value of numeric typed values, string and json values
+// are numeric if they hold a number, e.g. with ascii or json_ietf encoding
+func toFloat(tv *gnmi.TypedValue) (float64, bool) {
+	v, err := value.ToInterface(tv)
+	if err != nil {
+		return 0, false
+	}
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+func joinPath(prefix, p *gnmi.Path) []*gnmi.PathElem {
+	return append(append([]*gnmi.PathElem{}, prefix.GetElem()...), p.GetElem()...)
+}
+
+// pathString returns the path as a string with the keys sorted
+func pathString(p []*gnmi.PathElem) string {
+	sb := new(strings.Builder)
+	for _, pe := range p {
+		sb.WriteString("/" + pe.GetName())
+		keys := make([]string, 0, len(pe.GetKey()))
+		for k := range pe.GetKey() {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			sb.WriteString("[" + k + "=" + pe.GetKey()[k] + "]")
+		}
+	}
+	return sb.String()
+}
+
+// sanitize replaces the characters that are not allowed in prometheus names by an underscore
+func sanitize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == ':':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(l []string, s string) bool {
+	for _, e := range l {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,124 @@
+package promexporter
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+)
+
+func Test_Update(t *testing.T) {
+	upd := func(v *gnmi.TypedValue) *gnmi.Notification {
+		return &gnmi.Notification{
+			Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{
+				{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}},
+				{Name: "subinterface", Key: map[string]string{"index": "0"}},
+			}},
+			Update: []*gnmi.Update{{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "statistics"}, {Name: "in-octets"}}},
+				Val:  v,
+			}},
+		}
+	}
+	tests := []struct {
+		name string
+		cfg  *ygotnddpstate.YnddState_StateEntry_Prometheus
+		val  *gnmi.TypedValue
+		want string
+	}{
+		{
+			name: "path_name_and_all_keys",
+			val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 10}},
+			want: `interface_subinterface_statistics_in_octets{index="0",name="ethernet-1/1",target="leaf1"} 10`,
+		},
+		{
+			name: "metric_name_and_label_keys",
+			cfg: &ygotnddpstate.YnddState_StateEntry_Prometheus{
+				MetricName: ygot.String("srl_subitfce"),
+				LabelKey:   []string{"name"},
+			},
+			val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "20"}},
+			want: `srl_subitfce_in_octets{name="ethernet-1/1",target="leaf1"} 20`,
+		},
+		{
+			name: "not_numeric",
+			val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := New(WithLogger(logging.NewNopLogger()))
+			e.Update("leaf1", &ygotnddpstate.YnddState_StateEntry{
+				Name:       ygot.String("subitfce"),
+				Prometheus: tt.cfg,
+			}, upd(tt.val))
+
+			reg := prometheus.NewPedanticRegistry()
+			reg.MustRegister(e.(prometheus.Collector))
+			mfs, err := reg.Gather()
+			if err != nil {
+				t.Fatalf("Gather() error = %v", err)
+			}
+			if tt.want == "" {
+				if len(mfs) != 0 {
+					t.Errorf("Gather() got %d metrics, want none", len(mfs))
+				}
+				return
+			}
+			name := tt.want[:strings.Index(tt.want, "{")]
+			exp := "# HELP " + name + " collected state of /interface/subinterface/statistics/in-octets\n" +
+				"# TYPE " + name + " gauge\n" + tt.want + "\n"
+			if err := testutil.GatherAndCompare(reg, strings.NewReader(exp), name); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func Test_CollectOverlappingEntries(t *testing.T) {
+	n := &gnmi.Notification{
+		Prefix: &gnmi.Path{Elem: []*gnmi.PathElem{
+			{Name: "interface", Key: map[string]string{"name": "ethernet-1/1"}},
+		}},
+		Update: []*gnmi.Update{
+			{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{{Name: "statistics"}, {Name: "in-octets"}}},
+				Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 10}},
+			},
+			{
+				Path: &gnmi.Path{Elem: []*gnmi.PathElem{
+					{Name: "subinterface", Key: map[string]string{"index": "0"}},
+					{Name: "statistics"}, {Name: "in-octets"},
+				}},
+				Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 20}},
+			},
+		},
+	}
+	e := New(WithLogger(logging.NewNopLogger()))
+	// two state entries cover the same path
+	e.Update("leaf1", &ygotnddpstate.YnddState_StateEntry{Name: ygot.String("interface")}, n)
+	e.Update("leaf1", &ygotnddpstate.YnddState_StateEntry{Name: ygot.String("all")}, n)
+	// the metric name of the interface is used by the subinterface with an index label
+	e.Update("leaf1", &ygotnddpstate.YnddState_StateEntry{
+		Name:       ygot.String("renamed"),
+		Prometheus: &ygotnddpstate.YnddState_StateEntry_Prometheus{MetricName: ygot.String("interface_statistics")},
+	}, n)
+
+	reg := prometheus.NewPedanticRegistry()
+	reg.MustRegister(e.(prometheus.Collector))
+	exp := `# HELP interface_statistics_in_octets collected state of /interface/statistics/in-octets
+# TYPE interface_statistics_in_octets gauge
+interface_statistics_in_octets{name="ethernet-1/1",target="leaf1"} 10
+# HELP interface_subinterface_statistics_in_octets collected state of /interface/subinterface/statistics/in-octets
+# TYPE interface_subinterface_statistics_in_octets gauge
+interface_subinterface_statistics_in_octets{index="0",name="ethernet-1/1",target="leaf1"} 20
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(exp)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,170 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stategnmihandler
+
+import (
+	"sync"
+	"time"
+
+	"github.com/yndd/state/internal/collector"
+)
+
+const (
+	// DefaultReconcileDelay is the default debounce window of the collector reconciliation
+	DefaultReconcileDelay = 500 * time.Millisecond
+	// the reconciliation of a target that keeps changing is delayed at most
+	// maxReconcileDelayFactor times the debounce window
+	maxReconcileDelayFactor = 10
+)
+
+// clock creates the timers of the debouncer
+type clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) timer
+}
+
+// timer is the part of a time.Timer the debouncer uses
+type timer interface {
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time { return time.Now() }
+
+func (realClock) AfterFunc(d time.Duration, f func()) timer { return time.AfterFunc(d, f) }
+
+// debouncer coalesces the reconciliation requests of a target, the reconciliation
+// runs once the target did not change for the debounce window. A failed
+// reconciliation is retried with backoff until it succeeds or the target changes.
+type debouncer struct {
+	m        sync.Mutex
+	clock    clock
+	delay    time.Duration
+	maxDelay time.Duration
+	backoff  collector.Backoff
+	pending  map[string]*pendingReconcile
+	// consecutive failed reconciliations indexed by target
+	attempts map[string]int
+	// error of the last failed reconciliation indexed by target
+	errs map[string]reconcileError
+	fn   func(target string) error
+}
+
+// pendingReconcile is a scheduled reconciliation of a target
+type pendingReconcile struct {
+	timer timer
+	// time of the first request that is coalesced in the reconciliation
+	first time.Time
+}
+
+// reconcileError is the error of a failed reconciliation of a target
+type reconcileError struct {
+	err  string
+	time time.Time
+}
+
+func newDebouncer(delay time.Duration, backoff collector.Backoff, fn func(target string) error) *debouncer {
+	return &debouncer{
+		clock:    realClock{},
+		delay:    delay,
+		maxDelay: delay * maxReconcileDelayFactor,
+		backoff:  backoff,
+		pending:  map[string]*pendingReconcile{},
+		attempts: map[string]int{},
+		errs:     map[string]reconcileError{},
+		fn:       fn,
+	}
+}
+
+// lastError returns the error of the last reconciliation of a target if it failed
+func (d *debouncer) lastError(target string) (reconcileError, bool) {
+	d.m.Lock()
+	defer d.m.Unlock()
+	e, ok := d.errs[target]
+	return e, ok
+}
+
+// schedule (re)schedules the reconciliation of a target after the debounce window,
+// without a debounce window the target is reconciled synchronously and the error
+// of the reconciliation is returned. A scheduled retry of a failed reconciliation
+// is replaced.
+func (d *debouncer) schedule(target string) error {
+	if d.delay <= 0 {
+		d.m.Lock()
+		if p, ok := d.pending[target]; ok {
+			p.timer.Stop()
+			delete(d.pending, target)
+		}
+		d.m.Unlock()
+		return d.run(target)
+	}
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	if p, ok := d.pending[target]; ok && p.timer.Stop() {
+		// extend the pending reconciliation, bounded by the max delay
+		delay := d.delay
+		if rem := d.maxDelay - d.clock.Now().Sub(p.first); rem < delay {
+			delay = rem
+		}
+		p.timer.Reset(delay)
+		return nil
+	}
+	// no reconciliation pending or it is running already, schedule a new one
+	d.scheduleAfter(target, d.delay)
+	return nil
+}
+
+// scheduleAfter schedules the reconciliation of a target, the caller must hold the lock
+func (d *debouncer) scheduleAfter(target string, delay time.Duration) {
+	p := &pendingReconcile{first: d.clock.Now()}
+	p.timer = d.clock.AfterFunc(delay, func() {
+		d.m.Lock()
+		if d.pending[target] != p {
+			// replaced in the meantime
+			d.m.Unlock()
+			return
+		}
+		delete(d.pending, target)
+		d.m.Unlock()
+		// a failed reconciliation is retried and reported in the status of the entries
+		d.run(target)
+	})
+	d.pending[target] = p
+}
+
+// run reconciles a target and schedules a retry with backoff when it fails, unless a
+// reconciliation of the target was scheduled in the meantime
+func (d *debouncer) run(target string) error {
+	err := d.fn(target)
+
+	d.m.Lock()
+	defer d.m.Unlock()
+	if err == nil {
+		delete(d.attempts, target)
+		delete(d.errs, target)
+		return nil
+	}
+	d.attempts[target]++
+	d.errs[target] = reconcileError{err: err.Error(), time: d.clock.Now()}
+	if _, ok := d.pending[target]; !ok {
+		d.scheduleAfter(target, d.backoff.Duration(d.attempts[target]))
+	}
+	return err
+}
@@ -0,0 +1,176 @@
+package stategnmihandler
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yndd/state/internal/collector"
+)
+
+// fakeClock is a clock that only advances when told to, the expired timers run
+// synchronously in advance
+type fakeClock struct {
+	m      sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	c      *fakeClock
+	at     time.Time
+	f      func()
+	active bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Unix(0, 0)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.m.Lock()
+	defer c.m.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) timer {
+	c.m.Lock()
+	defer c.m.Unlock()
+	t := &fakeTimer{c: c, at: c.now.Add(d), f: f, active: true}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.c.m.Lock()
+	defer t.c.m.Unlock()
+	active := t.active
+	t.active = false
+	return active
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	t.c.m.Lock()
+	defer t.c.m.Unlock()
+	active := t.active
+	t.at = t.c.now.Add(d)
+	t.active = true
+	return active
+}
+
+// advance moves the clock forward and runs the timers that expire in the meantime in order
+func (c *fakeClock) advance(d time.Duration) {
+	c.m.Lock()
+	end := c.now.Add(d)
+	for {
+		var next *fakeTimer
+		for _, t := range c.timers {
+			if t.active && !t.at.After(end) && (next == nil || t.at.Before(next.at)) {
+				next = t
+			}
+		}
+		if next == nil {
+			break
+		}
+		c.now = next.at
+		next.active = false
+		c.m.Unlock()
+		next.f()
+		c.m.Lock()
+	}
+	c.now = end
+	c.m.Unlock()
+}
+
+func newTestDebouncer(delay time.Duration, backoff collector.Backoff, fn func(target string) error) (*debouncer, *fakeClock) {
+	d := newDebouncer(delay, backoff, fn)
+	c := newFakeClock()
+	d.clock = c
+	return d, c
+}
+
+func TestDebouncer(t *testing.T) {
+	calls := map[string]int{}
+	d, clock := newTestDebouncer(20*time.Millisecond, collector.DefaultBackoff(), func(target string) error {
+		calls[target]++
+		return nil
+	})
+
+	for i := 0; i < 30; i++ {
+		d.schedule("default/leaf1")
+		clock.advance(time.Millisecond)
+	}
+	d.schedule("default/leaf2")
+	if len(calls) != 0 {
+		t.Fatalf("reconcile calls within the debounce window = %v, want none", calls)
+	}
+	clock.advance(20 * time.Millisecond)
+
+	if calls["default/leaf1"] != 1 || calls["default/leaf2"] != 1 {
+		t.Errorf("reconcile calls = %v, want 1 per target", calls)
+	}
+}
+
+func TestDebouncerMaxDelay(t *testing.T) {
+	calls := 0
+	d, clock := newTestDebouncer(10*time.Millisecond, collector.DefaultBackoff(), func(target string) error {
+		calls++
+		return nil
+	})
+
+	// a target that keeps changing is reconciled after the max delay
+	start := clock.Now()
+	for calls == 0 {
+		if clock.Now().Sub(start) > d.maxDelay {
+			t.Fatal("target was not reconciled within the max delay")
+		}
+		d.schedule("default/leaf1")
+		clock.advance(2 * time.Millisecond)
+	}
+}
+
+func TestDebouncerRetry(t *testing.T) {
+	calls := 0
+	backoff := collector.Backoff{Initial: 10 * time.Millisecond, Max: 20 * time.Millisecond, Multiplier: 2}
+	d, clock := newTestDebouncer(10*time.Millisecond, backoff, func(target string) error {
+		calls++
+		if calls < 3 {
+			return errors.New("cache not ready")
+		}
+		return nil
+	})
+
+	// a failed reconciliation is retried with backoff without a new request until it succeeds
+	d.schedule("default/leaf1")
+	steps := []struct {
+		advance time.Duration
+		calls   int
+		failed  bool
+	}{
+		{advance: 10 * time.Millisecond, calls: 1, failed: true},
+		{advance: 10 * time.Millisecond, calls: 2, failed: true},
+		{advance: 10 * time.Millisecond, calls: 2, failed: true},
+		{advance: 10 * time.Millisecond, calls: 3},
+		{advance: time.Second, calls: 3},
+	}
+	for i, step := range steps {
+		clock.advance(step.advance)
+		if calls != step.calls {
+			t.Errorf("step %d: reconcile calls = %d, want %d", i, calls, step.calls)
+		}
+		if _, failed := d.lastError("default/leaf1"); failed != step.failed {
+			t.Errorf("step %d: failed = %v, want %v", i, failed, step.failed)
+		}
+	}
+}
+
+func TestDebouncerSynchronous(t *testing.T) {
+	d, _ := newTestDebouncer(0, collector.DefaultBackoff(), func(target string) error {
+		return errors.New("cache not ready")
+	})
+	// without a debounce window the error of the reconciliation is returned
+	if err := d.schedule("default/leaf1"); err == nil {
+		t.Errorf("schedule() without debounce window: want error")
+	}
+}
@@ -57,15 +57,14 @@ func (s *subServer) Set(ctx context.Context, p *gnmi.Path, upd *gnmi.Update) (*g
 		log.Debug("Set Update/Replace  target instance does not exist")
 		return nil, status.Errorf(codes.NotFound, errTargetNotFoundInCache)
 	}
-
-	tc, err := ti.GetTargetConfig()
-	if err != nil {
-		// target does not exist -> we can return an error
+	if _, err := ti.GetTargetConfig(); err != nil {
 		log.Debug("Set Update/Replace  target config not found")
 		return nil, status.Errorf(codes.NotFound, errTargetNotFoundInCache)
 	}
 
-	if err := s.collector.ReconcileTarget(tc); err != nil {
+	// the collector is reconciled once the changes of the target settled, a failed
+	// reconciliation is reported in the status of the state entries
+	if err := s.debouncer.schedule(p.GetTarget()); err != nil {
 		log.Debug("Set Update/Replace ReconcileTarget error", "error", err)
 		return nil, status.Errorf(codes.Internal, "ReconcileTarget error: %v", err)
 	}
@@ -103,15 +102,16 @@ func (s *subServer) Delete(ctx context.Context, p *gnmi.Path, del *gnmi.Path) (*
 		// target does not exist -> we can return an error
 		return nil, status.Errorf(codes.InvalidArgument, errTargetNotFoundInCache)
 	}
-
-	tc, err := ti.GetTargetConfig()
-	if err != nil {
-		// target does not exist -> we can return an error
-		return nil, status.Errorf(codes.InvalidArgument, errTargetNotFoundInCache)
+	if _, err := ti.GetTargetConfig(); err != nil {
+		log.Debug("Set Delete target config not found")
+		return nil, status.Errorf(codes.NotFound, errTargetNotFoundInCache)
 	}
 
-	if err := s.collector.ReconcileTarget(tc); err != nil {
-		return nil, status.Errorf(codes.Internal, "start target error: %v", err)
+	// the collector is reconciled once the changes of the target settled, a failed
+	// reconciliation is reported in the status of the state entries
+	if err := s.debouncer.schedule(p.GetTarget()); err != nil {
+		log.Debug("Set Delete ReconcileTarget error", "error", err)
+		return nil, status.Errorf(codes.Internal, "ReconcileTarget error: %v", err)
 	}
 
 	return &gnmi.SetResponse{
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 NDDO.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package stategnmihandler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/state/pkg/entrystatus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// GetStatus returns the status of the state entries of a target, a path with a
+// stateEntry name key restricts the response to that state entry and the target
+// path returns the reachability of the target
+func (s *subServer) GetStatus(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error) {
+	prefix := req.GetPrefix()
+	log := s.log.WithValues("origin", prefix.GetOrigin(), "target", prefix.GetTarget())
+	log.Debug("GetStatus...", "path", req.GetPath())
+
+	ti := s.stateTargetController.GetTargetInstance(prefix.GetTarget())
+	if ti == nil {
+		return nil, status.Errorf(codes.NotFound, errTargetNotFoundInCache)
+	}
+	tc, err := ti.GetTargetConfig()
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, errTargetNotFoundInCache)
+	}
+
+	if len(req.GetPath()) > 0 && entrystatus.IsTargetPath(req.GetPath()[0]) {
+		u, err := s.collector.GetTargetStatus(tc.Name).ToUpdate()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		return &gnmi.GetResponse{Notification: []*gnmi.Notification{{
+			Timestamp: time.Now().UnixNano(),
+			Prefix:    prefix,
+			Update:    []*gnmi.Update{u},
+		}}}, nil
+	}
+
+	es, err := s.collector.GetEntryStatus(tc.Name)
+	if err != nil {
+		log.Debug("GetStatus", "error", err)
+		return nil, status.Errorf(codes.Unavailable, "cache not ready")
+	}
+	// the state entries are not collected as configured while the reconciliation of
+	// the target fails
+	if re, ok := s.debouncer.lastError(prefix.GetTarget()); ok {
+		for name, st := range es {
+			if st.LastErrorTime.Before(re.time) {
+				st.LastError = fmt.Sprintf("cannot reconcile target: %s", re.err)
+				st.LastErrorTime = re.time
+				es[name] = st
+			}
+		}
+	}
+
+	names := make([]string, 0, len(es))
+	for name := range es {
+		if matchStateEntry(req.GetPath(), name) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil, status.Errorf(codes.NotFound, "state entry not found")
+	}
+	sort.Strings(names)
+
+	n := &gnmi.Notification{
+		Timestamp: time.Now().UnixNano(),
+		Prefix:    prefix,
+	}
+	for _, name := range names {
+		u, err := es[name].ToUpdate()
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, err.Error())
+		}
+		n.Update = append(n.Update, u)
+	}
+	return &gnmi.GetResponse{Notification: []*gnmi.Notification{n}}, nil
+}
+
+// matchStateEntry returns true if one of the paths selects the state entry, paths
+// without a stateEntry name key select all state entries
+func matchStateEntry(paths []*gnmi.Path, name string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		if len(p.GetElem()) == 0 {
+			return true
+		}
+		key, ok := p.GetElem()[0].GetKey()["name"]
+		if !ok || key == "*" || key == name {
+			return true
+		}
+	}
+	return false
+}
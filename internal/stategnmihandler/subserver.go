@@ -18,6 +18,7 @@ package stategnmihandler
 
 import (
 	"context"
+	"time"
 
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"github.com/yndd/cache/pkg/cache"
@@ -31,10 +32,16 @@ type Options struct {
 	Cache                 cache.Cache
 	Collector             collector.Collector
 	StateTargetController statetargetcontroller.StateTargetController
+	// ReconcileDelay is the debounce window of the collector reconciliation of a target,
+	// the collector is reconciled synchronously when it is 0
+	ReconcileDelay time.Duration
+	// ReconcileBackoff is the backoff between the retries of a failed reconciliation of a target
+	ReconcileBackoff collector.Backoff
 }
 
 type SubServer interface {
 	Get(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error)
+	GetStatus(ctx context.Context, req *gnmi.GetRequest) (*gnmi.GetResponse, error)
 	Set(ctx context.Context, p *gnmi.Path, upd *gnmi.Update) (*gnmi.SetResponse, error)
 	Delete(ctx context.Context, p *gnmi.Path, del *gnmi.Path) (*gnmi.SetResponse, error)
 }
@@ -46,6 +53,11 @@ func New(o *Options) SubServer {
 		collector:             o.Collector,
 		stateTargetController: o.StateTargetController,
 	}
+	backoff := o.ReconcileBackoff
+	if backoff.Initial <= 0 {
+		backoff = collector.DefaultBackoff()
+	}
+	s.debouncer = newDebouncer(o.ReconcileDelay, backoff, s.reconcileTarget)
 	return s
 }
 
@@ -54,4 +66,27 @@ type subServer struct {
 	cache                 cache.Cache
 	collector             collector.Collector
 	stateTargetController statetargetcontroller.StateTargetController
+	// coalesces the collector reconciliations of a target
+	debouncer *debouncer
+}
+
+// reconcileTarget aligns the collector of a target with its running config, an error
+// is returned when the reconciliation should be retried
+func (s *subServer) reconcileTarget(target string) error {
+	log := s.log.WithValues("target", target)
+	if s.stateTargetController.GetTargetInstance(target) == nil {
+		// the target is reconciled when it is started again
+		log.Debug("reconcile target: target not found")
+		return nil
+	}
+	tc, err := s.stateTargetController.GetTargetConfig(target)
+	if err != nil {
+		log.Debug("reconcile target: target config not found", "error", err)
+		return err
+	}
+	if err := s.collector.ReconcileTarget(tc); err != nil {
+		log.Debug("reconcile target error", "error", err)
+		return err
+	}
+	return nil
 }
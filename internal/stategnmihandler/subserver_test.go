@@ -0,0 +1,105 @@
+package stategnmihandler
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/karimra/gnmic/types"
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/yndd/ndd-runtime/pkg/logging"
+	"github.com/yndd/state/internal/collector"
+	"github.com/yndd/state/internal/statetargetcontroller"
+	"github.com/yndd/state/pkg/entrystatus"
+	"github.com/yndd/target/pkg/targetinstance"
+)
+
+type fakeTargetInstance struct {
+	targetinstance.TargetInstance
+	tc *types.TargetConfig
+}
+
+func (ti fakeTargetInstance) GetTargetConfig() (*types.TargetConfig, error) {
+	return ti.tc, nil
+}
+
+type fakeStateTargetController struct {
+	statetargetcontroller.StateTargetController
+	tc *types.TargetConfig
+}
+
+func (c fakeStateTargetController) GetTargetInstance(string) targetinstance.TargetInstance {
+	return fakeTargetInstance{tc: c.tc}
+}
+
+func (c fakeStateTargetController) GetTargetConfig(string) (*types.TargetConfig, error) {
+	return c.tc, nil
+}
+
+// fakeCollector fails the reconciliations of a target until fail is false
+type fakeCollector struct {
+	collector.Collector
+	fail  bool
+	calls []*types.TargetConfig
+}
+
+func (c *fakeCollector) ReconcileTarget(tc *types.TargetConfig) error {
+	c.calls = append(c.calls, tc)
+	if c.fail {
+		return errors.New("cannot start subscription")
+	}
+	return nil
+}
+
+func (c *fakeCollector) GetEntryStatus(string) (map[string]entrystatus.EntryStatus, error) {
+	return map[string]entrystatus.EntryStatus{"interface": {Name: "interface", Subscribed: true}}, nil
+}
+
+func TestReconcileTargetFailure(t *testing.T) {
+	const target = "default/leaf1"
+	tc := &types.TargetConfig{Name: target}
+	col := &fakeCollector{fail: true}
+	s := New(&Options{
+		Logger:                logging.NewNopLogger(),
+		Collector:             col,
+		StateTargetController: fakeStateTargetController{tc: tc},
+		ReconcileDelay:        10 * time.Millisecond,
+		ReconcileBackoff:      collector.Backoff{Initial: 10 * time.Millisecond, Multiplier: 1},
+	}).(*subServer)
+	clock := newFakeClock()
+	s.debouncer.clock = clock
+
+	entryStatus := func() entrystatus.EntryStatus {
+		resp, err := s.GetStatus(context.Background(), &gnmi.GetRequest{
+			Prefix: &gnmi.Path{Origin: entrystatus.Origin, Target: target},
+			Path:   []*gnmi.Path{entrystatus.Path("interface")},
+		})
+		if err != nil {
+			t.Fatalf("GetStatus() error = %v", err)
+		}
+		es, err := entrystatus.FromUpdate(resp.GetNotification()[0].GetUpdate()[0])
+		if err != nil {
+			t.Fatalf("FromUpdate() error = %v", err)
+		}
+		return es
+	}
+
+	// the failed reconciliation is retried with the unchanged config and reported in
+	// the status of the state entries
+	s.debouncer.schedule(target)
+	clock.advance(10 * time.Millisecond)
+	clock.advance(10 * time.Millisecond)
+	if len(col.calls) != 2 || col.calls[0] != col.calls[1] {
+		t.Fatalf("reconcile calls = %d, want 2 with the same config", len(col.calls))
+	}
+	if es := entryStatus(); es.LastError == "" {
+		t.Errorf("entry status after a failed reconciliation = %+v, want an error", es)
+	}
+
+	col.fail = false
+	clock.advance(10 * time.Millisecond)
+	if es := entryStatus(); es.LastError != "" {
+		t.Errorf("entry status after a successful reconciliation = %+v, want no error", es)
+	}
+}
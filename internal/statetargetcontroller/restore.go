@@ -0,0 +1,116 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statetargetcontroller
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+	"github.com/openconfig/ygot/ygot"
+	"github.com/pkg/errors"
+	"github.com/yndd/cache/pkg/cache"
+	"github.com/yndd/cache/pkg/model"
+	"github.com/yndd/cache/pkg/validator"
+	nddv1 "github.com/yndd/ndd-runtime/apis/common/v1"
+	statev1alpha1 "github.com/yndd/state/apis/state/v1alpha1"
+	"github.com/yndd/state/pkg/ygotnddpstate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// errors
+	errListStates        = "cannot list states"
+	errRestoreStateEntry = "cannot restore state entry"
+	errUnexpectedEntry   = "unexpected state entry object"
+)
+
+// stateEntryModel is the model the reconciler validates the spec of a State CR with
+var stateEntryModel = &model.Model{
+	StructRootType:  reflect.TypeOf((*ygotnddpstate.YnddState_StateEntry)(nil)),
+	SchemaTreeRoot:  ygotnddpstate.SchemaTree["NddpState_StateEntry"],
+	JsonUnmarshaler: ygotnddpstate.Unmarshal,
+	EnumData:        ygotnddpstate.ΛEnum,
+}
+
+// restoreRunningConfig rebuilds the running config of a target from the State CRs
+// that reference the target, such that the collection resumes after a restart of
+// the worker without waiting for the reconciler to recreate the state entries
+func (c *stateTargetController) restoreRunningConfig(ctx context.Context, ce cache.CacheEntry, namespace, targetName string) error {
+	log := c.log.WithValues("namespace", namespace, "targetName", targetName)
+
+	states := &statev1alpha1.StateList{}
+	if err := c.client.List(ctx, states, client.InNamespace(namespace)); err != nil {
+		return errors.Wrap(err, errListStates)
+	}
+
+	restored := 0
+	for _, cr := range states.Items {
+		if cr.GetDeletionTimestamp() != nil ||
+			cr.GetTargetReference() == nil || cr.GetTargetReference().Name != targetName {
+			continue
+		}
+		// planned states are not created by the reconciler
+		if p := cr.GetDeploymentPolicy(); p != "" && p != nddv1.DeploymentActive {
+			continue
+		}
+		u, err := stateEntryUpdate(&cr)
+		if err != nil {
+			// a state entry that cannot be restored is created by the reconciler
+			log.Debug(errRestoreStateEntry, "state", cr.GetName(), "error", err)
+			continue
+		}
+		// every state entry is validated on its own such that an invalid one does not
+		// prevent the others from being restored
+		if err := validator.ValidateUpdate(ce, []*gnmi.Update{u}, true, false, validator.Origin_GnmiServer); err != nil {
+			log.Debug(errRestoreStateEntry, "state", cr.GetName(), "error", err)
+			continue
+		}
+		restored++
+	}
+	log.Debug("restore running config", "state entries", restored)
+	return nil
+}
+
+// stateEntryUpdate returns the update the reconciler creates for a State CR
+func stateEntryUpdate(cr *statev1alpha1.State) (*gnmi.Update, error) {
+	gs, err := stateEntryModel.NewConfigStruct(cr.Spec.Properties.Raw, true)
+	if err != nil {
+		return nil, err
+	}
+	se, ok := gs.(*ygotnddpstate.YnddState_StateEntry)
+	if !ok {
+		return nil, errors.New(errUnexpectedEntry)
+	}
+	if se.Name == nil {
+		return nil, errors.New("state entry without name")
+	}
+	j, err := ygot.EmitJSON(se, &ygot.EmitJSONConfig{
+		Format: ygot.RFC7951,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &gnmi.Update{
+		Path: &gnmi.Path{
+			Elem: []*gnmi.PathElem{
+				{Name: "stateEntry", Key: map[string]string{"name": *se.Name}},
+			},
+		},
+		Val: &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(j)}},
+	}, nil
+}
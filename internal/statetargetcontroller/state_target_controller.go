@@ -20,6 +20,7 @@ import (
 	"context"
 	"sync"
 
+	"github.com/karimra/gnmic/types"
 	"github.com/openconfig/ygot/ygot"
 	"github.com/yndd/cache/pkg/cache"
 	"github.com/yndd/cache/pkg/model"
@@ -42,6 +43,8 @@ type StateTargetController interface {
 	StopTarget(nsTargetName string)
 	// get a target instance from the target state controller
 	GetTargetInstance(targetName string) targetinstance.TargetInstance
+	// get the target config of a target instance including its tls settings
+	GetTargetConfig(nsTargetName string) (*types.TargetConfig, error)
 }
 
 type Options struct {
@@ -62,6 +65,11 @@ type stateTargetController struct {
 	options *Options
 	m       sync.RWMutex
 	targets map[string]targetinstance.TargetInstance
+	// tls settings of the targets, indexed by target
+	tls map[string]*targetTLS
+	// directory the tls files of the targets are written to, the gnmi client reads
+	// them when dialing
+	tlsDir string
 
 	// kubernetes
 	client client.Client // used to get the target credentials
@@ -80,6 +88,7 @@ func New(ctx context.Context, o *Options, opts ...Option) StateTargetController
 		options: o, // contains all options
 		m:       sync.RWMutex{},
 		targets: make(map[string]targetinstance.TargetInstance),
+		tls:     make(map[string]*targetTLS),
 		ctx:     ctx,
 	}
 
@@ -87,6 +96,17 @@ func New(ctx context.Context, o *Options, opts ...Option) StateTargetController
 		opt(c)
 	}
 
+	// the collectors reconnect when the tls settings of their target change
+	go c.resyncTargetTLS(ctx)
+
+	// the tls files hold key material, remove them when the worker stops
+	go func() {
+		<-ctx.Done()
+		if err := c.deleteAllTLSFiles(); err != nil {
+			log.Debug("delete tls files", "error", err)
+		}
+	}()
+
 	return c
 }
 
@@ -101,6 +121,17 @@ func (c *stateTargetController) GetTargetInstance(nsTargetName string) targetins
 	return t
 }
 
+// targetNames returns the names of the target instances
+func (c *stateTargetController) targetNames() []string {
+	c.m.RLock()
+	defer c.m.RUnlock()
+	names := make([]string, 0, len(c.targets))
+	for name := range c.targets {
+		names = append(names, name)
+	}
+	return names
+}
+
 // add a target instance to the target configuration controller
 func (c *stateTargetController) addTargetInstance(nsTargetName string, t targetinstance.TargetInstance) {
 	c.m.Lock()
@@ -158,14 +189,17 @@ func (c *stateTargetController) StartTarget(nsTargetName string) {
 	if err := initRunningConfig(ce); err != nil {
 		log.Debug("start target: init running config failed", "error", err)
 	}
+	// rebuild the running config from the State CRs, e.g. after a restart of the worker
+	if err := c.restoreRunningConfig(c.ctx, ce, namespace, targetName); err != nil {
+		log.Debug("start target: restore running config failed", "error", err)
+	}
 	c.options.Cache.AddEntry(ce)
 
 	// start the target in the collector if there is a running config
-	tc, err := ti.GetTargetConfig()
+	tc, err := c.GetTargetConfig(nsTargetName)
 	if err != nil {
 		c.log.Debug("cannot get target config", "error", err)
-	}
-	if err := c.options.Collector.ReconcileTarget(tc); err != nil {
+	} else if err := c.options.Collector.ReconcileTarget(tc); err != nil {
 		c.log.Debug("start target state", "error", err)
 	}
 	// register service discovery
@@ -185,6 +219,9 @@ func (c *stateTargetController) StopTarget(nsTargetName string) {
 	}
 
 	c.deleteTargetInstance(nsTargetName)
+	if err := c.deleteTargetTLS(nsTargetName); err != nil {
+		log.Debug("delete target tls files", "error", err)
+	}
 	log.Debug("deleted target...")
 }
 
@@ -0,0 +1,271 @@
+/*
+Copyright 2022 NDD.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package statetargetcontroller
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/karimra/gnmic/types"
+	"github.com/pkg/errors"
+	"github.com/yndd/ndd-runtime/pkg/meta"
+	targetv1 "github.com/yndd/target/apis/target/v1"
+	corev1 "k8s.io/api/core/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// keys of the tls secret referenced by the Target CR
+	secretKeyCA   = "ca.crt"
+	secretKeyCert = "tls.crt"
+	secretKeyKey  = "tls.key"
+
+	// errors
+	errTargetNotFound = "target instance not found"
+	errGetTarget      = "cannot get target"
+	errGetTLSSecret   = "cannot get target tls secret"
+	errWriteTLSFiles  = "cannot write target tls files"
+
+	// interval between the checks for changes of the tls settings of the targets
+	tlsResyncInterval = time.Minute
+)
+
+// targetTLS are the tls settings of a target, they are read from the Target CR and
+// the tls secret it references and reloaded when either of them changes
+type targetTLS struct {
+	insecure   bool
+	skipVerify bool
+	// tls files indexed by secret key
+	files map[string]string
+	// resource versions of the Target CR and the tls secret the settings are read from
+	version string
+}
+
+// GetTargetConfig returns the target config of a target instance, completed with the
+// tls settings of the Target CR and the certificates of the tls secret it references
+func (c *stateTargetController) GetTargetConfig(nsTargetName string) (*types.TargetConfig, error) {
+	ti := c.GetTargetInstance(nsTargetName)
+	if ti == nil {
+		return nil, errors.New(errTargetNotFound)
+	}
+	tc, err := ti.GetTargetConfig()
+	if err != nil {
+		return nil, err
+	}
+	tt, _, err := c.getTargetTLS(c.ctx, nsTargetName)
+	if err != nil {
+		return nil, err
+	}
+	tt.setTargetConfig(tc)
+	return tc, nil
+}
+
+// getTargetTLS returns the tls settings of a target and true if they changed since
+// they were last loaded, they are reloaded when the resource version of the Target CR
+// or of its tls secret changed
+func (c *stateTargetController) getTargetTLS(ctx context.Context, nsTargetName string) (*targetTLS, bool, error) {
+	t, secret, err := c.getTLSResources(ctx, nsTargetName)
+	if err != nil {
+		return nil, false, err
+	}
+	version := t.GetResourceVersion()
+	if secret != nil {
+		version += "/" + secret.GetResourceVersion()
+	}
+	c.m.RLock()
+	cached, ok := c.tls[nsTargetName]
+	c.m.RUnlock()
+	if ok && cached.version == version {
+		return cached, false, nil
+	}
+
+	tt := &targetTLS{version: version}
+	if cfg := targetConfig(t); cfg != nil {
+		tt.insecure = cfg.Insecure
+		tt.skipVerify = cfg.SkipVerify
+	}
+	if secret != nil {
+		// the files of every version of the secret have their own names, such that the
+		// collector sees the change and reconnects with the new certificates
+		files, err := c.writeTLSFiles(nsTargetName, secret.GetResourceVersion(), secret.Data)
+		if err != nil {
+			return nil, false, errors.Wrap(err, errWriteTLSFiles)
+		}
+		tt.files = files
+	}
+	c.m.Lock()
+	defer c.m.Unlock()
+	c.tls[nsTargetName] = tt
+	return tt, ok, nil
+}
+
+// getTLSResources returns the Target CR of a target and the tls secret it references,
+// the secret is nil when the Target CR references none
+func (c *stateTargetController) getTLSResources(ctx context.Context, nsTargetName string) (*targetv1.Target, *corev1.Secret, error) {
+	namespace := meta.NamespacedName(nsTargetName).GetNameSpace()
+	t := &targetv1.Target{}
+	if err := c.client.Get(ctx, k8stypes.NamespacedName{
+		Namespace: namespace,
+		Name:      meta.NamespacedName(nsTargetName).GetName(),
+	}, t); err != nil {
+		return nil, nil, errors.Wrap(err, errGetTarget)
+	}
+	cfg := targetConfig(t)
+	if cfg == nil || cfg.TlsCredentialName == "" {
+		return t, nil, nil
+	}
+	secret := &corev1.Secret{}
+	if err := c.client.Get(ctx, k8stypes.NamespacedName{Namespace: namespace, Name: cfg.TlsCredentialName}, secret); err != nil {
+		return nil, nil, errors.Wrap(err, errGetTLSSecret)
+	}
+	return t, secret, nil
+}
+
+// targetConfig returns the connection config of a Target CR, nil if it has none
+func targetConfig(t *targetv1.Target) *targetv1.TargetConfig {
+	if t.Spec.Properties == nil {
+		return nil
+	}
+	return t.Spec.Properties.Config
+}
+
+// resyncTargetTLS reloads the tls settings of the targets periodically, the collector
+// of a target whose Target CR or tls secret changed is reconciled and reconnects
+func (c *stateTargetController) resyncTargetTLS(ctx context.Context) {
+	ticker := time.NewTicker(tlsResyncInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, nsTargetName := range c.targetNames() {
+				c.reconcileTargetTLS(ctx, nsTargetName)
+			}
+		}
+	}
+}
+
+// reconcileTargetTLS reconciles the collector of a target when its tls settings changed
+func (c *stateTargetController) reconcileTargetTLS(ctx context.Context, nsTargetName string) {
+	log := c.log.WithValues("nsTargetName", nsTargetName)
+	_, changed, err := c.getTargetTLS(ctx, nsTargetName)
+	if err != nil {
+		log.Debug("reload target tls", "error", err)
+		return
+	}
+	if !changed {
+		return
+	}
+	tc, err := c.GetTargetConfig(nsTargetName)
+	if err != nil {
+		log.Debug("cannot get target config", "error", err)
+		return
+	}
+	if !c.options.Collector.IsActive(tc.Name) {
+		return
+	}
+	log.Debug("target tls changed, reconciling target")
+	if err := c.options.Collector.ReconcileTarget(tc); err != nil {
+		log.Debug("reconcile target tls", "error", err)
+	}
+}
+
+// setTargetConfig sets the insecure and skip verify settings and the tls files of the target config
+func (tt *targetTLS) setTargetConfig(tc *types.TargetConfig) {
+	insecure := tt.insecure
+	tc.Insecure = &insecure
+	// the gnmi client dereferences skip verify when tls is used
+	skipVerify := tt.skipVerify
+	tc.SkipVerify = &skipVerify
+	if f, ok := tt.files[secretKeyCA]; ok {
+		tc.TLSCA = &f
+	}
+	if f, ok := tt.files[secretKeyCert]; ok {
+		tc.TLSCert = &f
+	}
+	if f, ok := tt.files[secretKeyKey]; ok {
+		tc.TLSKey = &f
+	}
+}
+
+// writeTLSFiles writes the certificates of a version of a tls secret to files and
+// returns the file names indexed by secret key
+func (c *stateTargetController) writeTLSFiles(nsTargetName, version string, data map[string][]byte) (map[string]string, error) {
+	targetDir, err := c.targetTLSDir(nsTargetName)
+	if err != nil {
+		return nil, err
+	}
+	// remove the files of a previous version of the secret
+	if err := os.RemoveAll(targetDir); err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(targetDir, version)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	files := map[string]string{}
+	for _, k := range []string{secretKeyCA, secretKeyCert, secretKeyKey} {
+		if len(data[k]) == 0 {
+			continue
+		}
+		f := filepath.Join(dir, k)
+		if err := os.WriteFile(f, data[k], 0600); err != nil {
+			return nil, err
+		}
+		files[k] = f
+	}
+	return files, nil
+}
+
+// targetTLSDir returns the directory of the tls files of a target, the directory of the
+// tls files of all targets is created when the first target uses tls
+func (c *stateTargetController) targetTLSDir(nsTargetName string) (string, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.tlsDir == "" {
+		dir, err := os.MkdirTemp("", "state-worker-tls-")
+		if err != nil {
+			return "", err
+		}
+		c.tlsDir = dir
+	}
+	return filepath.Join(c.tlsDir, filepath.FromSlash(nsTargetName)), nil
+}
+
+// deleteTargetTLS removes the tls settings and the tls files of a target
+func (c *stateTargetController) deleteTargetTLS(nsTargetName string) error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	delete(c.tls, nsTargetName)
+	if c.tlsDir == "" {
+		return nil
+	}
+	return os.RemoveAll(filepath.Join(c.tlsDir, filepath.FromSlash(nsTargetName)))
+}
+
+// deleteAllTLSFiles removes the tls files of all targets
+func (c *stateTargetController) deleteAllTLSFiles() error {
+	c.m.Lock()
+	defer c.m.Unlock()
+	if c.tlsDir == "" {
+		return nil
+	}
+	return os.RemoveAll(c.tlsDir)
+}
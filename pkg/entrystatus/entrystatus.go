@@ -0,0 +1,113 @@
+package entrystatus
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// Origin is the gnmi origin the worker serves the status of the state entries on
+const Origin = "status"
+
+// EntryStatus is the status of the collection of a single state entry as reported
+// by the worker
+type EntryStatus struct {
+	// Name of the state entry
+	Name string `json:"name"`
+	// Subscribed indicates the subscription of the state entry is running
+	Subscribed bool `json:"subscribed"`
+	// Synced indicates the initial sync of the running subscription completed
+	Synced bool `json:"synced"`
+	// Rejected indicates the state entry is not subscribed since the target does not support it
+	Rejected bool `json:"rejected,omitempty"`
+	// Message explains why the state entry is rejected or which settings are downgraded
+	// to what the target supports
+	Message string `json:"message,omitempty"`
+	// LastError is the last error the subscription of the state entry or the target reported
+	LastError string `json:"last-error,omitempty"`
+	// LastErrorTime is the time the last error was reported
+	LastErrorTime time.Time `json:"last-error-time,omitempty"`
+	// LastUpdate is the time the last update was received for the state entry
+	LastUpdate time.Time `json:"last-update,omitempty"`
+}
+
+// TargetStatus is the reachability of a target as reported by the worker
+type TargetStatus struct {
+	// Target is the namespaced name of the target
+	Target string `json:"target"`
+	// Reachable indicates the worker is connected to the target and the connection
+	// did not fail since
+	Reachable bool `json:"reachable"`
+	// Pending indicates the worker did not connect to the target yet and no attempt
+	// failed, the reachability is unknown
+	Pending bool `json:"pending,omitempty"`
+	// Attempt is the number of consecutive failed attempts to connect to the target, 0
+	// if the last attempt succeeded
+	Attempt int `json:"attempt,omitempty"`
+	// NextRetry is the time of the next attempt to connect to the target
+	NextRetry time.Time `json:"next-retry,omitempty"`
+	// LastError is the last error the target reported
+	LastError string `json:"last-error,omitempty"`
+	// LastErrorTime is the time the last error was reported
+	LastErrorTime time.Time `json:"last-error-time,omitempty"`
+}
+
+// TargetPath returns the gnmi path of the status of the target
+func TargetPath() *gnmi.Path {
+	return &gnmi.Path{
+		Elem: []*gnmi.PathElem{{Name: "target"}},
+	}
+}
+
+// IsTargetPath returns true if the path selects the status of the target
+func IsTargetPath(p *gnmi.Path) bool {
+	return len(p.GetElem()) > 0 && p.GetElem()[0].GetName() == "target"
+}
+
+// ToUpdate returns the target status as a gnmi update with a json value
+func (s TargetStatus) ToUpdate() (*gnmi.Update, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return &gnmi.Update{
+		Path: TargetPath(),
+		Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: b}},
+	}, nil
+}
+
+// TargetFromUpdate returns the target status encoded in a gnmi update
+func TargetFromUpdate(u *gnmi.Update) (TargetStatus, error) {
+	s := TargetStatus{}
+	err := json.Unmarshal(u.GetVal().GetJsonVal(), &s)
+	return s, err
+}
+
+// Path returns the gnmi path of the status of a state entry
+func Path(name string) *gnmi.Path {
+	return &gnmi.Path{
+		Elem: []*gnmi.PathElem{
+			{Name: "stateEntry", Key: map[string]string{"name": name}},
+		},
+	}
+}
+
+// ToUpdate returns the status as a gnmi update with a json value
+func (s EntryStatus) ToUpdate() (*gnmi.Update, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return &gnmi.Update{
+		Path: Path(s.Name),
+		Val:  &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: b}},
+	}, nil
+}
+
+// FromUpdate returns the status encoded in a gnmi update
+func FromUpdate(u *gnmi.Update) (EntryStatus, error) {
+	s := EntryStatus{}
+	err := json.Unmarshal(u.GetVal().GetJsonVal(), &s)
+	return s, err
+}
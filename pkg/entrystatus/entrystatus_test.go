@@ -0,0 +1,62 @@
+package entrystatus
+
+import (
+	"testing"
+	"time"
+)
+
+func Test_EntryStatusUpdate(t *testing.T) {
+	now := time.Now().Round(0)
+	tests := []struct {
+		name string
+		s    EntryStatus
+	}{
+		{
+			name: "synced",
+			s:    EntryStatus{Name: "itfce", Subscribed: true, Synced: true, LastUpdate: now},
+		},
+		{
+			name: "failed",
+			s:    EntryStatus{Name: "itfce", LastError: "connection refused", LastErrorTime: now},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := tt.s.ToUpdate()
+			if err != nil {
+				t.Fatalf("ToUpdate() error = %v", err)
+			}
+			if got := u.GetPath().GetElem()[0].GetKey()["name"]; got != tt.s.Name {
+				t.Errorf("ToUpdate() path key = %q, want %q", got, tt.s.Name)
+			}
+			got, err := FromUpdate(u)
+			if err != nil {
+				t.Fatalf("FromUpdate() error = %v", err)
+			}
+			if got.Name != tt.s.Name || got.Subscribed != tt.s.Subscribed || got.Synced != tt.s.Synced ||
+				got.LastError != tt.s.LastError || !got.LastErrorTime.Equal(tt.s.LastErrorTime) ||
+				!got.LastUpdate.Equal(tt.s.LastUpdate) {
+				t.Errorf("FromUpdate() = %+v, want %+v", got, tt.s)
+			}
+		})
+	}
+}
+
+func Test_TargetStatusUpdate(t *testing.T) {
+	s := TargetStatus{Target: "ndd-system/leaf1", LastError: "connection refused", LastErrorTime: time.Now().Round(0)}
+	u, err := s.ToUpdate()
+	if err != nil {
+		t.Fatalf("ToUpdate() error = %v", err)
+	}
+	if !IsTargetPath(u.GetPath()) {
+		t.Errorf("ToUpdate() path = %v, want target path", u.GetPath())
+	}
+	got, err := TargetFromUpdate(u)
+	if err != nil {
+		t.Fatalf("TargetFromUpdate() error = %v", err)
+	}
+	if got.Target != s.Target || got.Reachable != s.Reachable || got.LastError != s.LastError ||
+		!got.LastErrorTime.Equal(s.LastErrorTime) {
+		t.Errorf("TargetFromUpdate() = %+v, want %+v", got, s)
+	}
+}
@@ -13,6 +13,9 @@ import (
 const (
 	dotReplChar   = "^"
 	spaceReplChar = "~"
+	// the nats wildcards are replaced such that a key is never a wildcard token
+	starReplChar = "%"
+	gtReplChar   = "!"
 )
 
 var errMalformedXPath = errors.New("malformed xpath")
@@ -21,6 +24,8 @@ var escapedBracketsReplacer = strings.NewReplacer(`\]`, `]`, `\[`, `[`)
 
 var regDot = regexp.MustCompile(`\.`)
 var regSpace = regexp.MustCompile(`\s`)
+var regStar = regexp.MustCompile(`\*`)
+var regGt = regexp.MustCompile(`>`)
 
 func GNMIPathToSubject(p *gnmi.Path) string {
 	if p == nil {
@@ -221,7 +226,15 @@ func parseXPathKeys(s string) ([]string, map[string]string, error) {
 	return keys, kvs, nil
 }
 
+// SanitizeToken replaces the characters of s that have a special meaning in a subject,
+// so that s can be used as a single token of a subject.
+func SanitizeToken(s string) string {
+	return sanitizeKey(s)
+}
+
 func sanitizeKey(k string) string {
 	s := regDot.ReplaceAllString(k, dotReplChar)
+	s = regStar.ReplaceAllString(s, starReplChar)
+	s = regGt.ReplaceAllString(s, gtReplChar)
 	return regSpace.ReplaceAllString(s, spaceReplChar)
 }
@@ -234,6 +234,25 @@ func Test_GNMIPathToSubject(t *testing.T) {
 			},
 			want: "foo.{k1=1^1^1^1}.{k2=2^2^2^2}",
 		},
+		{
+			// /foo[k1=*][k2=a>b]
+			name: "path_with_elem_keys_containing_wildcards",
+			args: args{
+				p: &gnmi.Path{
+					Origin: "",
+					Elem: []*gnmi.PathElem{
+						{
+							Name: "foo",
+							Key: map[string]string{
+								"k1": "*",
+								"k2": "a>b",
+							},
+						},
+					},
+				},
+			},
+			want: "foo.{k1=%}.{k2=a!b}",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -359,6 +378,52 @@ func TestXPathToSubject(t *testing.T) {
 	}
 }
 
+func TestSanitizeToken(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{
+			name: "plain",
+			s:    "itfce",
+			want: "itfce",
+		},
+		{
+			name: "with_dots",
+			s:    "leaf1.sim.1a-b0-02-ff-00-00",
+			want: "leaf1^sim^1a-b0-02-ff-00-00",
+		},
+		{
+			name: "with_spaces",
+			s:    "interface stats",
+			want: "interface~stats",
+		},
+		{
+			name: "star_wildcard",
+			s:    "*",
+			want: "%",
+		},
+		{
+			name: "gt_wildcard",
+			s:    ">",
+			want: "!",
+		},
+		{
+			name: "with_wildcards",
+			s:    "queue*>high",
+			want: "queue%!high",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SanitizeToken(tt.s); got != tt.want {
+				t.Errorf("SanitizeToken() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func BenchmarkXPathToSubject(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		XPathToSubject("origin:/foo[k2=v2][k1=*]/bar[a=1][b=*]")
@@ -0,0 +1,159 @@
+package value
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+// value types
+const (
+	TypeString   = "string"
+	TypeInt      = "int"
+	TypeUint     = "uint"
+	TypeBool     = "bool"
+	TypeBytes    = "bytes"
+	TypeFloat    = "float"
+	TypeDouble   = "double"
+	TypeDecimal  = "decimal"
+	TypeLeaflist = "leaflist"
+	TypeAny      = "any"
+	TypeJSON     = "json"
+	TypeJSONIETF = "json_ietf"
+	TypeASCII    = "ascii"
+	TypeProto    = "proto"
+)
+
+var errUnknownValueType = errors.New("unknown value type")
+
+// ToBytes converts a gnmi typed value into its byte representation and returns the type of the value.
+// Scalar values are rendered as their string representation, json values are returned as is and
+// leaf lists are rendered as a json array.
+func ToBytes(tv *gnmi.TypedValue) ([]byte, string, error) {
+	if tv == nil {
+		return nil, "", nil
+	}
+	switch v := tv.GetValue().(type) {
+	case *gnmi.TypedValue_StringVal:
+		return []byte(v.StringVal), TypeString, nil
+	case *gnmi.TypedValue_IntVal:
+		return []byte(strconv.FormatInt(v.IntVal, 10)), TypeInt, nil
+	case *gnmi.TypedValue_UintVal:
+		return []byte(strconv.FormatUint(v.UintVal, 10)), TypeUint, nil
+	case *gnmi.TypedValue_BoolVal:
+		return []byte(strconv.FormatBool(v.BoolVal)), TypeBool, nil
+	case *gnmi.TypedValue_BytesVal:
+		return v.BytesVal, TypeBytes, nil
+	case *gnmi.TypedValue_FloatVal:
+		return []byte(strconv.FormatFloat(float64(v.FloatVal), 'g', -1, 32)), TypeFloat, nil
+	case *gnmi.TypedValue_DoubleVal:
+		return []byte(strconv.FormatFloat(v.DoubleVal, 'g', -1, 64)), TypeDouble, nil
+	case *gnmi.TypedValue_DecimalVal:
+		return []byte(decimalToString(v.DecimalVal)), TypeDecimal, nil
+	case *gnmi.TypedValue_LeaflistVal:
+		elems := make([]interface{}, 0, len(v.LeaflistVal.GetElement()))
+		for _, e := range v.LeaflistVal.GetElement() {
+			ev, err := ToInterface(e)
+			if err != nil {
+				return nil, "", err
+			}
+			elems = append(elems, ev)
+		}
+		b, err := json.Marshal(elems)
+		if err != nil {
+			return nil, "", err
+		}
+		return b, TypeLeaflist, nil
+	case *gnmi.TypedValue_AnyVal:
+		return v.AnyVal.GetValue(), TypeAny, nil
+	case *gnmi.TypedValue_JsonVal:
+		return v.JsonVal, TypeJSON, nil
+	case *gnmi.TypedValue_JsonIetfVal:
+		return v.JsonIetfVal, TypeJSONIETF, nil
+	case *gnmi.TypedValue_AsciiVal:
+		return []byte(v.AsciiVal), TypeASCII, nil
+	case *gnmi.TypedValue_ProtoBytes:
+		return v.ProtoBytes, TypeProto, nil
+	default:
+		return nil, "", fmt.Errorf("%w: %T", errUnknownValueType, v)
+	}
+}
+
+// ToInterface converts a gnmi typed value into a go value, json values are decoded.
+func ToInterface(tv *gnmi.TypedValue) (interface{}, error) {
+	if tv == nil {
+		return nil, nil
+	}
+	switch v := tv.GetValue().(type) {
+	case *gnmi.TypedValue_StringVal:
+		return v.StringVal, nil
+	case *gnmi.TypedValue_IntVal:
+		return v.IntVal, nil
+	case *gnmi.TypedValue_UintVal:
+		return v.UintVal, nil
+	case *gnmi.TypedValue_BoolVal:
+		return v.BoolVal, nil
+	case *gnmi.TypedValue_BytesVal:
+		return v.BytesVal, nil
+	case *gnmi.TypedValue_FloatVal:
+		return v.FloatVal, nil
+	case *gnmi.TypedValue_DoubleVal:
+		return v.DoubleVal, nil
+	case *gnmi.TypedValue_DecimalVal:
+		return strconv.ParseFloat(decimalToString(v.DecimalVal), 64)
+	case *gnmi.TypedValue_LeaflistVal:
+		elems := make([]interface{}, 0, len(v.LeaflistVal.GetElement()))
+		for _, e := range v.LeaflistVal.GetElement() {
+			ev, err := ToInterface(e)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, ev)
+		}
+		return elems, nil
+	case *gnmi.TypedValue_AnyVal:
+		return v.AnyVal.GetValue(), nil
+	case *gnmi.TypedValue_JsonVal:
+		return decodeJSON(v.JsonVal)
+	case *gnmi.TypedValue_JsonIetfVal:
+		return decodeJSON(v.JsonIetfVal)
+	case *gnmi.TypedValue_AsciiVal:
+		return v.AsciiVal, nil
+	case *gnmi.TypedValue_ProtoBytes:
+		return v.ProtoBytes, nil
+	default:
+		return nil, fmt.Errorf("%w: %T", errUnknownValueType, v)
+	}
+}
+
+func decodeJSON(b []byte) (interface{}, error) {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// decimalToString renders a decimal64 value without loss of precision, e.g. digits 12345 with
+// precision 2 is rendered as 123.45
+func decimalToString(d *gnmi.Decimal64) string {
+	digits := d.GetDigits()
+	precision := int(d.GetPrecision())
+	if precision == 0 {
+		return strconv.FormatInt(digits, 10)
+	}
+	sign := ""
+	s := strconv.FormatInt(digits, 10)
+	if digits < 0 {
+		sign = "-"
+		s = s[1:]
+	}
+	if len(s) <= precision {
+		s = strings.Repeat("0", precision-len(s)+1) + s
+	}
+	return sign + s[:len(s)-precision] + "." + s[len(s)-precision:]
+}
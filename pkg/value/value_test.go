@@ -0,0 +1,127 @@
+package value
+
+import (
+	"testing"
+
+	"github.com/openconfig/gnmi/proto/gnmi"
+)
+
+func TestToBytes(t *testing.T) {
+	tests := []struct {
+		name     string
+		tv       *gnmi.TypedValue
+		want     string
+		wantType string
+		wantErr  bool
+	}{
+		{
+			name:     "nil",
+			tv:       nil,
+			want:     "",
+			wantType: "",
+		},
+		{
+			name:     "string",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_StringVal{StringVal: "up"}},
+			want:     "up",
+			wantType: TypeString,
+		},
+		{
+			name:     "int",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_IntVal{IntVal: -42}},
+			want:     "-42",
+			wantType: TypeInt,
+		},
+		{
+			name:     "uint",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_UintVal{UintVal: 18446744073709551615}},
+			want:     "18446744073709551615",
+			wantType: TypeUint,
+		},
+		{
+			name:     "bool",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_BoolVal{BoolVal: true}},
+			want:     "true",
+			wantType: TypeBool,
+		},
+		{
+			name:     "float",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_FloatVal{FloatVal: 1.5}},
+			want:     "1.5",
+			wantType: TypeFloat,
+		},
+		{
+			name:     "double",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_DoubleVal{DoubleVal: 0.1}},
+			want:     "0.1",
+			wantType: TypeDouble,
+		},
+		{
+			name:     "decimal",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_DecimalVal{DecimalVal: &gnmi.Decimal64{Digits: 12345, Precision: 2}}},
+			want:     "123.45",
+			wantType: TypeDecimal,
+		},
+		{
+			name:     "decimal_smaller_than_one",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_DecimalVal{DecimalVal: &gnmi.Decimal64{Digits: -5, Precision: 3}}},
+			want:     "-0.005",
+			wantType: TypeDecimal,
+		},
+		{
+			name:     "decimal_no_precision",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_DecimalVal{DecimalVal: &gnmi.Decimal64{Digits: 7}}},
+			want:     "7",
+			wantType: TypeDecimal,
+		},
+		{
+			name:     "json",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonVal{JsonVal: []byte(`{"a":1}`)}},
+			want:     `{"a":1}`,
+			wantType: TypeJSON,
+		},
+		{
+			name:     "json_ietf",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_JsonIetfVal{JsonIetfVal: []byte(`"up"`)}},
+			want:     `"up"`,
+			wantType: TypeJSONIETF,
+		},
+		{
+			name:     "ascii",
+			tv:       &gnmi.TypedValue{Value: &gnmi.TypedValue_AsciiVal{AsciiVal: "up"}},
+			want:     "up",
+			wantType: TypeASCII,
+		},
+		{
+			name: "leaflist",
+			tv: &gnmi.TypedValue{Value: &gnmi.TypedValue_LeaflistVal{LeaflistVal: &gnmi.ScalarArray{
+				Element: []*gnmi.TypedValue{
+					{Value: &gnmi.TypedValue_StringVal{StringVal: "a"}},
+					{Value: &gnmi.TypedValue_UintVal{UintVal: 1}},
+				},
+			}}},
+			want:     `["a",1]`,
+			wantType: TypeLeaflist,
+		},
+		{
+			name:    "unset",
+			tv:      &gnmi.TypedValue{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotType, err := ToBytes(tt.tv)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ToBytes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("ToBytes() = %v, want %v", string(got), tt.want)
+			}
+			if gotType != tt.wantType {
+				t.Errorf("ToBytes() type = %v, want %v", gotType, tt.wantType)
+			}
+		})
+	}
+}
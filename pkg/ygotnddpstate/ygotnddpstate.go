@@ -275,9 +275,19 @@ func (*Device) ΛBelongingModule() string {
 
 // YnddState_StateEntry represents the /yndd-state/stateEntry YANG schema element.
 type YnddState_StateEntry struct {
-	Name   *string  `path:"name" module:"yndd-state"`
-	Path   []string `path:"path" module:"yndd-state"`
-	Prefix *string  `path:"prefix" module:"yndd-state"`
+	ChangesOnly       *bool                                      `path:"changes-only" module:"yndd-state"`
+	DeleteAfterResync *bool                                      `path:"delete-after-resync" module:"yndd-state"`
+	Encoding          E_YnddState_StateEntry_Encoding            `path:"encoding" module:"yndd-state"`
+	HeartbeatInterval *string                                    `path:"heartbeat-interval" module:"yndd-state"`
+	Mode              E_YnddState_StateEntry_Mode                `path:"mode" module:"yndd-state"`
+	Name              *string                                    `path:"name" module:"yndd-state"`
+	Path              []string                                   `path:"path" module:"yndd-state"`
+	Prefix            *string                                    `path:"prefix" module:"yndd-state"`
+	Processor         map[uint32]*YnddState_StateEntry_Processor `path:"processor" module:"yndd-state"`
+	Prometheus        *YnddState_StateEntry_Prometheus           `path:"prometheus" module:"yndd-state"`
+	SampleInterval    *string                                    `path:"sample-interval" module:"yndd-state"`
+	StaleAfter        *string                                    `path:"stale-after" module:"yndd-state"`
+	SuppressRedundant *bool                                      `path:"suppress-redundant" module:"yndd-state"`
 }
 
 // IsYANGGoStruct ensures that YnddState_StateEntry implements the yang.GoStruct
@@ -285,6 +295,124 @@ type YnddState_StateEntry struct {
 // identify it as being generated by ygen.
 func (*YnddState_StateEntry) IsYANGGoStruct() {}
 
+// NewProcessor creates a new entry in the Processor list of the
+// YnddState_StateEntry struct. The keys of the list are populated from the input
+// arguments.
+func (t *YnddState_StateEntry) NewProcessor(Index uint32) (*YnddState_StateEntry_Processor, error) {
+
+	// Initialise the list within the receiver struct if it has not already been
+	// created.
+	if t.Processor == nil {
+		t.Processor = make(map[uint32]*YnddState_StateEntry_Processor)
+	}
+
+	key := Index
+
+	// Ensure that this key has not already been used in the
+	// list. Keyed YANG lists do not allow duplicate keys to
+	// be created.
+	if _, ok := t.Processor[key]; ok {
+		return nil, fmt.Errorf("duplicate key %v for list Processor", key)
+	}
+
+	t.Processor[key] = &YnddState_StateEntry_Processor{
+		Index: &Index,
+	}
+
+	return t.Processor[key], nil
+}
+
+// GetOrCreateProcessor retrieves the value with the specified keys from
+// the receiver YnddState_StateEntry. If the entry does not exist, then it is created.
+// It returns the existing or new list member.
+func (t *YnddState_StateEntry) GetOrCreateProcessor(Index uint32) *YnddState_StateEntry_Processor {
+
+	key := Index
+
+	if v, ok := t.Processor[key]; ok {
+		return v
+	}
+	// Panic if we receive an error, since we should have retrieved an existing
+	// list member. This allows chaining of GetOrCreate methods.
+	v, err := t.NewProcessor(Index)
+	if err != nil {
+		panic(fmt.Sprintf("GetOrCreateProcessor got unexpected error: %v", err))
+	}
+	return v
+}
+
+// GetProcessor retrieves the value with the specified key from
+// the Processor map field of YnddState_StateEntry. If the receiver is nil, or
+// the specified key is not present in the list, nil is returned such that Get*
+// methods may be safely chained.
+func (t *YnddState_StateEntry) GetProcessor(Index uint32) *YnddState_StateEntry_Processor {
+
+	if t == nil {
+		return nil
+	}
+
+	key := Index
+
+	if lm, ok := t.Processor[key]; ok {
+		return lm
+	}
+	return nil
+}
+
+// DeleteProcessor deletes the value with the specified keys from
+// the receiver YnddState_StateEntry. If there is no such element, the function
+// is a no-op.
+func (t *YnddState_StateEntry) DeleteProcessor(Index uint32) {
+	key := Index
+
+	delete(t.Processor, key)
+}
+
+// AppendProcessor appends the supplied YnddState_StateEntry_Processor struct to the
+// list Processor of YnddState_StateEntry. If the key value(s) specified in
+// the supplied YnddState_StateEntry_Processor already exist in the list, an error is
+// returned.
+func (t *YnddState_StateEntry) AppendProcessor(v *YnddState_StateEntry_Processor) error {
+	if v.Index == nil {
+		return fmt.Errorf("invalid nil key received for Index")
+	}
+
+	key := *v.Index
+
+	// Initialise the list within the receiver struct if it has not already been
+	// created.
+	if t.Processor == nil {
+		t.Processor = make(map[uint32]*YnddState_StateEntry_Processor)
+	}
+
+	if _, ok := t.Processor[key]; ok {
+		return fmt.Errorf("duplicate key for list Processor %v", key)
+	}
+
+	t.Processor[key] = v
+	return nil
+}
+
+// GetOrCreatePrometheus retrieves the value of the Prometheus field
+// or returns the existing field if it already exists.
+func (t *YnddState_StateEntry) GetOrCreatePrometheus() *YnddState_StateEntry_Prometheus {
+	if t.Prometheus != nil {
+		return t.Prometheus
+	}
+	t.Prometheus = &YnddState_StateEntry_Prometheus{}
+	return t.Prometheus
+}
+
+// GetPrometheus returns the value of the Prometheus struct pointer
+// from YnddState_StateEntry. If the receiver or the field Prometheus is nil, nil
+// is returned such that the Get* methods can be safely chained.
+func (t *YnddState_StateEntry) GetPrometheus() *YnddState_StateEntry_Prometheus {
+	if t != nil && t.Prometheus != nil {
+		return t.Prometheus
+	}
+	return nil
+}
+
 // PopulateDefaults recursively populates unset leaf fields in the YnddState_StateEntry
 // with default values as specified in the YANG schema, instantiating any nil
 // container fields.
@@ -293,6 +421,28 @@ func (t *YnddState_StateEntry) PopulateDefaults() {
 		return
 	}
 	ygot.BuildEmptyTree(t)
+	if t.ChangesOnly == nil {
+		var v bool = false
+		t.ChangesOnly = &v
+	}
+	if t.DeleteAfterResync == nil {
+		var v bool = false
+		t.DeleteAfterResync = &v
+	}
+	if t.Encoding == 0 {
+		t.Encoding = YnddState_StateEntry_Encoding_ascii
+	}
+	if t.Mode == 0 {
+		t.Mode = YnddState_StateEntry_Mode_on_change
+	}
+	if t.SuppressRedundant == nil {
+		var v bool = false
+		t.SuppressRedundant = &v
+	}
+	t.Prometheus.PopulateDefaults()
+	for _, e := range t.Processor {
+		e.PopulateDefaults()
+	}
 }
 
 // ΛListKeyMap returns the keys of the YnddState_StateEntry struct, which is a YANG list entry.
@@ -329,6 +479,606 @@ func (*YnddState_StateEntry) ΛBelongingModule() string {
 	return "yndd-state"
 }
 
+// YnddState_StateEntry_Processor represents the /yndd-state/stateEntry/processor YANG schema element.
+type YnddState_StateEntry_Processor struct {
+	ConvertTo   E_YnddState_StateEntry_Processor_ConvertTo          `path:"convert-to" module:"yndd-state"`
+	Index       *uint32                                             `path:"index" module:"yndd-state"`
+	PathMatch   *string                                             `path:"path-match" module:"yndd-state"`
+	Replacement *string                                             `path:"replacement" module:"yndd-state"`
+	Tag         map[string]*YnddState_StateEntry_Processor_Tag      `path:"tag" module:"yndd-state"`
+	Type        E_YnddState_StateEntry_Processor_Type               `path:"type" module:"yndd-state"`
+	ValueMap    map[string]*YnddState_StateEntry_Processor_ValueMap `path:"value-map" module:"yndd-state"`
+	ValueMatch  *string                                             `path:"value-match" module:"yndd-state"`
+}
+
+// IsYANGGoStruct ensures that YnddState_StateEntry_Processor implements the yang.GoStruct
+// interface. This allows functions that need to handle this struct to
+// identify it as being generated by ygen.
+func (*YnddState_StateEntry_Processor) IsYANGGoStruct() {}
+
+// NewTag creates a new entry in the Tag list of the
+// YnddState_StateEntry_Processor struct. The keys of the list are populated from the input
+// arguments.
+func (t *YnddState_StateEntry_Processor) NewTag(Name string) (*YnddState_StateEntry_Processor_Tag, error) {
+
+	// Initialise the list within the receiver struct if it has not already been
+	// created.
+	if t.Tag == nil {
+		t.Tag = make(map[string]*YnddState_StateEntry_Processor_Tag)
+	}
+
+	key := Name
+
+	// Ensure that this key has not already been used in the
+	// list. Keyed YANG lists do not allow duplicate keys to
+	// be created.
+	if _, ok := t.Tag[key]; ok {
+		return nil, fmt.Errorf("duplicate key %v for list Tag", key)
+	}
+
+	t.Tag[key] = &YnddState_StateEntry_Processor_Tag{
+		Name: &Name,
+	}
+
+	return t.Tag[key], nil
+}
+
+// GetOrCreateTag retrieves the value with the specified keys from
+// the receiver YnddState_StateEntry_Processor. If the entry does not exist, then it is created.
+// It returns the existing or new list member.
+func (t *YnddState_StateEntry_Processor) GetOrCreateTag(Name string) *YnddState_StateEntry_Processor_Tag {
+
+	key := Name
+
+	if v, ok := t.Tag[key]; ok {
+		return v
+	}
+	// Panic if we receive an error, since we should have retrieved an existing
+	// list member. This allows chaining of GetOrCreate methods.
+	v, err := t.NewTag(Name)
+	if err != nil {
+		panic(fmt.Sprintf("GetOrCreateTag got unexpected error: %v", err))
+	}
+	return v
+}
+
+// GetTag retrieves the value with the specified key from
+// the Tag map field of YnddState_StateEntry_Processor. If the receiver is nil, or
+// the specified key is not present in the list, nil is returned such that Get*
+// methods may be safely chained.
+func (t *YnddState_StateEntry_Processor) GetTag(Name string) *YnddState_StateEntry_Processor_Tag {
+
+	if t == nil {
+		return nil
+	}
+
+	key := Name
+
+	if lm, ok := t.Tag[key]; ok {
+		return lm
+	}
+	return nil
+}
+
+// DeleteTag deletes the value with the specified keys from
+// the receiver YnddState_StateEntry_Processor. If there is no such element, the function
+// is a no-op.
+func (t *YnddState_StateEntry_Processor) DeleteTag(Name string) {
+	key := Name
+
+	delete(t.Tag, key)
+}
+
+// AppendTag appends the supplied YnddState_StateEntry_Processor_Tag struct to the
+// list Tag of YnddState_StateEntry_Processor. If the key value(s) specified in
+// the supplied YnddState_StateEntry_Processor_Tag already exist in the list, an error is
+// returned.
+func (t *YnddState_StateEntry_Processor) AppendTag(v *YnddState_StateEntry_Processor_Tag) error {
+	if v.Name == nil {
+		return fmt.Errorf("invalid nil key received for Name")
+	}
+
+	key := *v.Name
+
+	// Initialise the list within the receiver struct if it has not already been
+	// created.
+	if t.Tag == nil {
+		t.Tag = make(map[string]*YnddState_StateEntry_Processor_Tag)
+	}
+
+	if _, ok := t.Tag[key]; ok {
+		return fmt.Errorf("duplicate key for list Tag %v", key)
+	}
+
+	t.Tag[key] = v
+	return nil
+}
+
+// NewValueMap creates a new entry in the ValueMap list of the
+// YnddState_StateEntry_Processor struct. The keys of the list are populated from the input
+// arguments.
+func (t *YnddState_StateEntry_Processor) NewValueMap(From string) (*YnddState_StateEntry_Processor_ValueMap, error) {
+
+	// Initialise the list within the receiver struct if it has not already been
+	// created.
+	if t.ValueMap == nil {
+		t.ValueMap = make(map[string]*YnddState_StateEntry_Processor_ValueMap)
+	}
+
+	key := From
+
+	// Ensure that this key has not already been used in the
+	// list. Keyed YANG lists do not allow duplicate keys to
+	// be created.
+	if _, ok := t.ValueMap[key]; ok {
+		return nil, fmt.Errorf("duplicate key %v for list ValueMap", key)
+	}
+
+	t.ValueMap[key] = &YnddState_StateEntry_Processor_ValueMap{
+		From: &From,
+	}
+
+	return t.ValueMap[key], nil
+}
+
+// GetOrCreateValueMap retrieves the value with the specified keys from
+// the receiver YnddState_StateEntry_Processor. If the entry does not exist, then it is created.
+// It returns the existing or new list member.
+func (t *YnddState_StateEntry_Processor) GetOrCreateValueMap(From string) *YnddState_StateEntry_Processor_ValueMap {
+
+	key := From
+
+	if v, ok := t.ValueMap[key]; ok {
+		return v
+	}
+	// Panic if we receive an error, since we should have retrieved an existing
+	// list member. This allows chaining of GetOrCreate methods.
+	v, err := t.NewValueMap(From)
+	if err != nil {
+		panic(fmt.Sprintf("GetOrCreateValueMap got unexpected error: %v", err))
+	}
+	return v
+}
+
+// GetValueMap retrieves the value with the specified key from
+// the ValueMap map field of YnddState_StateEntry_Processor. If the receiver is nil, or
+// the specified key is not present in the list, nil is returned such that Get*
+// methods may be safely chained.
+func (t *YnddState_StateEntry_Processor) GetValueMap(From string) *YnddState_StateEntry_Processor_ValueMap {
+
+	if t == nil {
+		return nil
+	}
+
+	key := From
+
+	if lm, ok := t.ValueMap[key]; ok {
+		return lm
+	}
+	return nil
+}
+
+// DeleteValueMap deletes the value with the specified keys from
+// the receiver YnddState_StateEntry_Processor. If there is no such element, the function
+// is a no-op.
+func (t *YnddState_StateEntry_Processor) DeleteValueMap(From string) {
+	key := From
+
+	delete(t.ValueMap, key)
+}
+
+// AppendValueMap appends the supplied YnddState_StateEntry_Processor_ValueMap struct to the
+// list ValueMap of YnddState_StateEntry_Processor. If the key value(s) specified in
+// the supplied YnddState_StateEntry_Processor_ValueMap already exist in the list, an error is
+// returned.
+func (t *YnddState_StateEntry_Processor) AppendValueMap(v *YnddState_StateEntry_Processor_ValueMap) error {
+	if v.From == nil {
+		return fmt.Errorf("invalid nil key received for From")
+	}
+
+	key := *v.From
+
+	// Initialise the list within the receiver struct if it has not already been
+	// created.
+	if t.ValueMap == nil {
+		t.ValueMap = make(map[string]*YnddState_StateEntry_Processor_ValueMap)
+	}
+
+	if _, ok := t.ValueMap[key]; ok {
+		return fmt.Errorf("duplicate key for list ValueMap %v", key)
+	}
+
+	t.ValueMap[key] = v
+	return nil
+}
+
+// PopulateDefaults recursively populates unset leaf fields in the YnddState_StateEntry_Processor
+// with default values as specified in the YANG schema, instantiating any nil
+// container fields.
+func (t *YnddState_StateEntry_Processor) PopulateDefaults() {
+	if t == nil {
+		return
+	}
+	ygot.BuildEmptyTree(t)
+	for _, e := range t.Tag {
+		e.PopulateDefaults()
+	}
+	for _, e := range t.ValueMap {
+		e.PopulateDefaults()
+	}
+}
+
+// ΛListKeyMap returns the keys of the YnddState_StateEntry_Processor struct, which is a YANG list entry.
+func (t *YnddState_StateEntry_Processor) ΛListKeyMap() (map[string]interface{}, error) {
+	if t.Index == nil {
+		return nil, fmt.Errorf("nil value for key Index")
+	}
+
+	return map[string]interface{}{
+		"index": *t.Index,
+	}, nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Processor) ΛValidate(opts ...ygot.ValidationOption) error {
+	if err := ytypes.Validate(SchemaTree["YnddState_StateEntry_Processor"], t, opts...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Processor) Validate(opts ...ygot.ValidationOption) error {
+	return t.ΛValidate(opts...)
+}
+
+// ΛEnumTypeMap returns a map, keyed by YANG schema path, of the enumerated types
+// that are included in the generated code.
+func (t *YnddState_StateEntry_Processor) ΛEnumTypeMap() map[string][]reflect.Type {
+	return ΛEnumTypes
+}
+
+// ΛBelongingModule returns the name of the module that defines the namespace
+// of YnddState_StateEntry_Processor.
+func (*YnddState_StateEntry_Processor) ΛBelongingModule() string {
+	return "yndd-state"
+}
+
+// YnddState_StateEntry_Processor_Tag represents the /yndd-state/stateEntry/processor/tag YANG schema element.
+type YnddState_StateEntry_Processor_Tag struct {
+	Name  *string `path:"name" module:"yndd-state"`
+	Value *string `path:"value" module:"yndd-state"`
+}
+
+// IsYANGGoStruct ensures that YnddState_StateEntry_Processor_Tag implements the yang.GoStruct
+// interface. This allows functions that need to handle this struct to
+// identify it as being generated by ygen.
+func (*YnddState_StateEntry_Processor_Tag) IsYANGGoStruct() {}
+
+// PopulateDefaults recursively populates unset leaf fields in the YnddState_StateEntry_Processor_Tag
+// with default values as specified in the YANG schema, instantiating any nil
+// container fields.
+func (t *YnddState_StateEntry_Processor_Tag) PopulateDefaults() {
+	if t == nil {
+		return
+	}
+	ygot.BuildEmptyTree(t)
+}
+
+// ΛListKeyMap returns the keys of the YnddState_StateEntry_Processor_Tag struct, which is a YANG list entry.
+func (t *YnddState_StateEntry_Processor_Tag) ΛListKeyMap() (map[string]interface{}, error) {
+	if t.Name == nil {
+		return nil, fmt.Errorf("nil value for key Name")
+	}
+
+	return map[string]interface{}{
+		"name": *t.Name,
+	}, nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Processor_Tag) ΛValidate(opts ...ygot.ValidationOption) error {
+	if err := ytypes.Validate(SchemaTree["YnddState_StateEntry_Processor_Tag"], t, opts...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Processor_Tag) Validate(opts ...ygot.ValidationOption) error {
+	return t.ΛValidate(opts...)
+}
+
+// ΛEnumTypeMap returns a map, keyed by YANG schema path, of the enumerated types
+// that are included in the generated code.
+func (t *YnddState_StateEntry_Processor_Tag) ΛEnumTypeMap() map[string][]reflect.Type {
+	return ΛEnumTypes
+}
+
+// ΛBelongingModule returns the name of the module that defines the namespace
+// of YnddState_StateEntry_Processor_Tag.
+func (*YnddState_StateEntry_Processor_Tag) ΛBelongingModule() string {
+	return "yndd-state"
+}
+
+// YnddState_StateEntry_Processor_ValueMap represents the /yndd-state/stateEntry/processor/value-map YANG schema element.
+type YnddState_StateEntry_Processor_ValueMap struct {
+	From *string `path:"from" module:"yndd-state"`
+	To   *string `path:"to" module:"yndd-state"`
+}
+
+// IsYANGGoStruct ensures that YnddState_StateEntry_Processor_ValueMap implements the yang.GoStruct
+// interface. This allows functions that need to handle this struct to
+// identify it as being generated by ygen.
+func (*YnddState_StateEntry_Processor_ValueMap) IsYANGGoStruct() {}
+
+// PopulateDefaults recursively populates unset leaf fields in the YnddState_StateEntry_Processor_ValueMap
+// with default values as specified in the YANG schema, instantiating any nil
+// container fields.
+func (t *YnddState_StateEntry_Processor_ValueMap) PopulateDefaults() {
+	if t == nil {
+		return
+	}
+	ygot.BuildEmptyTree(t)
+}
+
+// ΛListKeyMap returns the keys of the YnddState_StateEntry_Processor_ValueMap struct, which is a YANG list entry.
+func (t *YnddState_StateEntry_Processor_ValueMap) ΛListKeyMap() (map[string]interface{}, error) {
+	if t.From == nil {
+		return nil, fmt.Errorf("nil value for key From")
+	}
+
+	return map[string]interface{}{
+		"from": *t.From,
+	}, nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Processor_ValueMap) ΛValidate(opts ...ygot.ValidationOption) error {
+	if err := ytypes.Validate(SchemaTree["YnddState_StateEntry_Processor_ValueMap"], t, opts...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Processor_ValueMap) Validate(opts ...ygot.ValidationOption) error {
+	return t.ΛValidate(opts...)
+}
+
+// ΛEnumTypeMap returns a map, keyed by YANG schema path, of the enumerated types
+// that are included in the generated code.
+func (t *YnddState_StateEntry_Processor_ValueMap) ΛEnumTypeMap() map[string][]reflect.Type {
+	return ΛEnumTypes
+}
+
+// ΛBelongingModule returns the name of the module that defines the namespace
+// of YnddState_StateEntry_Processor_ValueMap.
+func (*YnddState_StateEntry_Processor_ValueMap) ΛBelongingModule() string {
+	return "yndd-state"
+}
+
+// YnddState_StateEntry_Prometheus represents the /yndd-state/stateEntry/prometheus YANG schema element.
+type YnddState_StateEntry_Prometheus struct {
+	Enabled    *bool    `path:"enabled" module:"yndd-state"`
+	LabelKey   []string `path:"label-key" module:"yndd-state"`
+	MetricName *string  `path:"metric-name" module:"yndd-state"`
+}
+
+// IsYANGGoStruct ensures that YnddState_StateEntry_Prometheus implements the yang.GoStruct
+// interface. This allows functions that need to handle this struct to
+// identify it as being generated by ygen.
+func (*YnddState_StateEntry_Prometheus) IsYANGGoStruct() {}
+
+// PopulateDefaults recursively populates unset leaf fields in the YnddState_StateEntry_Prometheus
+// with default values as specified in the YANG schema, instantiating any nil
+// container fields.
+func (t *YnddState_StateEntry_Prometheus) PopulateDefaults() {
+	if t == nil {
+		return
+	}
+	ygot.BuildEmptyTree(t)
+	if t.Enabled == nil {
+		var v bool = true
+		t.Enabled = &v
+	}
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Prometheus) ΛValidate(opts ...ygot.ValidationOption) error {
+	if err := ytypes.Validate(SchemaTree["YnddState_StateEntry_Prometheus"], t, opts...); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates s against the YANG schema corresponding to its type.
+func (t *YnddState_StateEntry_Prometheus) Validate(opts ...ygot.ValidationOption) error {
+	return t.ΛValidate(opts...)
+}
+
+// ΛEnumTypeMap returns a map, keyed by YANG schema path, of the enumerated types
+// that are included in the generated code.
+func (t *YnddState_StateEntry_Prometheus) ΛEnumTypeMap() map[string][]reflect.Type {
+	return ΛEnumTypes
+}
+
+// ΛBelongingModule returns the name of the module that defines the namespace
+// of YnddState_StateEntry_Prometheus.
+func (*YnddState_StateEntry_Prometheus) ΛBelongingModule() string {
+	return "yndd-state"
+}
+
+// E_YnddState_StateEntry_Encoding is a derived int64 type which is used to represent
+// the enumerated node YnddState_StateEntry_Encoding. An additional value named
+// YnddState_StateEntry_Encoding_UNSET is added to the enumeration which is used as
+// the nil value, indicating that the enumeration was not explicitly set by
+// the program importing the generated structures.
+type E_YnddState_StateEntry_Encoding int64
+
+// IsYANGGoEnum ensures that YnddState_StateEntry_Encoding implements the yang.GoEnum
+// interface. This ensures that YnddState_StateEntry_Encoding can be identified as a
+// mapped type for a YANG enumeration.
+func (E_YnddState_StateEntry_Encoding) IsYANGGoEnum() {}
+
+// ΛMap returns the value lookup map associated with  YnddState_StateEntry_Encoding.
+func (E_YnddState_StateEntry_Encoding) ΛMap() map[string]map[int64]ygot.EnumDefinition {
+	return ΛEnum
+}
+
+// String returns a logging-friendly string for E_YnddState_StateEntry_Encoding.
+func (e E_YnddState_StateEntry_Encoding) String() string {
+	return ygot.EnumLogString(e, int64(e), "E_YnddState_StateEntry_Encoding")
+}
+
+const (
+	// YnddState_StateEntry_Encoding_UNSET corresponds to the value UNSET of YnddState_StateEntry_Encoding
+	YnddState_StateEntry_Encoding_UNSET E_YnddState_StateEntry_Encoding = 0
+	// YnddState_StateEntry_Encoding_ascii corresponds to the value ascii of YnddState_StateEntry_Encoding
+	YnddState_StateEntry_Encoding_ascii E_YnddState_StateEntry_Encoding = 1
+	// YnddState_StateEntry_Encoding_json corresponds to the value json of YnddState_StateEntry_Encoding
+	YnddState_StateEntry_Encoding_json E_YnddState_StateEntry_Encoding = 2
+	// YnddState_StateEntry_Encoding_json_ietf corresponds to the value json_ietf of YnddState_StateEntry_Encoding
+	YnddState_StateEntry_Encoding_json_ietf E_YnddState_StateEntry_Encoding = 3
+	// YnddState_StateEntry_Encoding_proto corresponds to the value proto of YnddState_StateEntry_Encoding
+	YnddState_StateEntry_Encoding_proto E_YnddState_StateEntry_Encoding = 4
+)
+
+// E_YnddState_StateEntry_Mode is a derived int64 type which is used to represent
+// the enumerated node YnddState_StateEntry_Mode. An additional value named
+// YnddState_StateEntry_Mode_UNSET is added to the enumeration which is used as
+// the nil value, indicating that the enumeration was not explicitly set by
+// the program importing the generated structures.
+type E_YnddState_StateEntry_Mode int64
+
+// IsYANGGoEnum ensures that YnddState_StateEntry_Mode implements the yang.GoEnum
+// interface. This ensures that YnddState_StateEntry_Mode can be identified as a
+// mapped type for a YANG enumeration.
+func (E_YnddState_StateEntry_Mode) IsYANGGoEnum() {}
+
+// ΛMap returns the value lookup map associated with  YnddState_StateEntry_Mode.
+func (E_YnddState_StateEntry_Mode) ΛMap() map[string]map[int64]ygot.EnumDefinition { return ΛEnum }
+
+// String returns a logging-friendly string for E_YnddState_StateEntry_Mode.
+func (e E_YnddState_StateEntry_Mode) String() string {
+	return ygot.EnumLogString(e, int64(e), "E_YnddState_StateEntry_Mode")
+}
+
+const (
+	// YnddState_StateEntry_Mode_UNSET corresponds to the value UNSET of YnddState_StateEntry_Mode
+	YnddState_StateEntry_Mode_UNSET E_YnddState_StateEntry_Mode = 0
+	// YnddState_StateEntry_Mode_on_change corresponds to the value on_change of YnddState_StateEntry_Mode
+	YnddState_StateEntry_Mode_on_change E_YnddState_StateEntry_Mode = 1
+	// YnddState_StateEntry_Mode_sample corresponds to the value sample of YnddState_StateEntry_Mode
+	YnddState_StateEntry_Mode_sample E_YnddState_StateEntry_Mode = 2
+	// YnddState_StateEntry_Mode_target_defined corresponds to the value target_defined of YnddState_StateEntry_Mode
+	YnddState_StateEntry_Mode_target_defined E_YnddState_StateEntry_Mode = 3
+	// YnddState_StateEntry_Mode_poll corresponds to the value poll of YnddState_StateEntry_Mode
+	YnddState_StateEntry_Mode_poll E_YnddState_StateEntry_Mode = 4
+)
+
+// E_YnddState_StateEntry_Processor_ConvertTo is a derived int64 type which is used to represent
+// the enumerated node YnddState_StateEntry_Processor_ConvertTo. An additional value named
+// YnddState_StateEntry_Processor_ConvertTo_UNSET is added to the enumeration which is used as
+// the nil value, indicating that the enumeration was not explicitly set by
+// the program importing the generated structures.
+type E_YnddState_StateEntry_Processor_ConvertTo int64
+
+// IsYANGGoEnum ensures that YnddState_StateEntry_Processor_ConvertTo implements the yang.GoEnum
+// interface. This ensures that YnddState_StateEntry_Processor_ConvertTo can be identified as a
+// mapped type for a YANG enumeration.
+func (E_YnddState_StateEntry_Processor_ConvertTo) IsYANGGoEnum() {}
+
+// ΛMap returns the value lookup map associated with  YnddState_StateEntry_Processor_ConvertTo.
+func (E_YnddState_StateEntry_Processor_ConvertTo) ΛMap() map[string]map[int64]ygot.EnumDefinition {
+	return ΛEnum
+}
+
+// String returns a logging-friendly string for E_YnddState_StateEntry_Processor_ConvertTo.
+func (e E_YnddState_StateEntry_Processor_ConvertTo) String() string {
+	return ygot.EnumLogString(e, int64(e), "E_YnddState_StateEntry_Processor_ConvertTo")
+}
+
+const (
+	// YnddState_StateEntry_Processor_ConvertTo_UNSET corresponds to the value UNSET of YnddState_StateEntry_Processor_ConvertTo
+	YnddState_StateEntry_Processor_ConvertTo_UNSET E_YnddState_StateEntry_Processor_ConvertTo = 0
+	// YnddState_StateEntry_Processor_ConvertTo_int corresponds to the value int of YnddState_StateEntry_Processor_ConvertTo
+	YnddState_StateEntry_Processor_ConvertTo_int E_YnddState_StateEntry_Processor_ConvertTo = 1
+	// YnddState_StateEntry_Processor_ConvertTo_uint corresponds to the value uint of YnddState_StateEntry_Processor_ConvertTo
+	YnddState_StateEntry_Processor_ConvertTo_uint E_YnddState_StateEntry_Processor_ConvertTo = 2
+	// YnddState_StateEntry_Processor_ConvertTo_float corresponds to the value float of YnddState_StateEntry_Processor_ConvertTo
+	YnddState_StateEntry_Processor_ConvertTo_float E_YnddState_StateEntry_Processor_ConvertTo = 3
+)
+
+// E_YnddState_StateEntry_Processor_Type is a derived int64 type which is used to represent
+// the enumerated node YnddState_StateEntry_Processor_Type. An additional value named
+// YnddState_StateEntry_Processor_Type_UNSET is added to the enumeration which is used as
+// the nil value, indicating that the enumeration was not explicitly set by
+// the program importing the generated structures.
+type E_YnddState_StateEntry_Processor_Type int64
+
+// IsYANGGoEnum ensures that YnddState_StateEntry_Processor_Type implements the yang.GoEnum
+// interface. This ensures that YnddState_StateEntry_Processor_Type can be identified as a
+// mapped type for a YANG enumeration.
+func (E_YnddState_StateEntry_Processor_Type) IsYANGGoEnum() {}
+
+// ΛMap returns the value lookup map associated with  YnddState_StateEntry_Processor_Type.
+func (E_YnddState_StateEntry_Processor_Type) ΛMap() map[string]map[int64]ygot.EnumDefinition {
+	return ΛEnum
+}
+
+// String returns a logging-friendly string for E_YnddState_StateEntry_Processor_Type.
+func (e E_YnddState_StateEntry_Processor_Type) String() string {
+	return ygot.EnumLogString(e, int64(e), "E_YnddState_StateEntry_Processor_Type")
+}
+
+const (
+	// YnddState_StateEntry_Processor_Type_UNSET corresponds to the value UNSET of YnddState_StateEntry_Processor_Type
+	YnddState_StateEntry_Processor_Type_UNSET E_YnddState_StateEntry_Processor_Type = 0
+	// YnddState_StateEntry_Processor_Type_drop corresponds to the value drop of YnddState_StateEntry_Processor_Type
+	YnddState_StateEntry_Processor_Type_drop E_YnddState_StateEntry_Processor_Type = 1
+	// YnddState_StateEntry_Processor_Type_rename corresponds to the value rename of YnddState_StateEntry_Processor_Type
+	YnddState_StateEntry_Processor_Type_rename E_YnddState_StateEntry_Processor_Type = 2
+	// YnddState_StateEntry_Processor_Type_convert corresponds to the value convert of YnddState_StateEntry_Processor_Type
+	YnddState_StateEntry_Processor_Type_convert E_YnddState_StateEntry_Processor_Type = 3
+	// YnddState_StateEntry_Processor_Type_map_value corresponds to the value map_value of YnddState_StateEntry_Processor_Type
+	YnddState_StateEntry_Processor_Type_map_value E_YnddState_StateEntry_Processor_Type = 4
+	// YnddState_StateEntry_Processor_Type_add_tag corresponds to the value add_tag of YnddState_StateEntry_Processor_Type
+	YnddState_StateEntry_Processor_Type_add_tag E_YnddState_StateEntry_Processor_Type = 5
+)
+
+// ΛEnum is a map, keyed by the name of the type defined for each enum in the
+// generated Go code, which provides a mapping between the constant int64 value
+// of each value of the enumeration, and the string that is used to represent it
+// in the YANG schema. The map is named ΛEnum in order to avoid clash with any
+// valid YANG identifier.
+var ΛEnum = map[string]map[int64]ygot.EnumDefinition{
+	"E_YnddState_StateEntry_Encoding": {
+		1: {Name: "ascii"},
+		2: {Name: "json"},
+		3: {Name: "json_ietf"},
+		4: {Name: "proto"},
+	},
+	"E_YnddState_StateEntry_Mode": {
+		1: {Name: "on-change"},
+		2: {Name: "sample"},
+		3: {Name: "target-defined"},
+		4: {Name: "poll"},
+	},
+	"E_YnddState_StateEntry_Processor_ConvertTo": {
+		1: {Name: "int"},
+		2: {Name: "uint"},
+		3: {Name: "float"},
+	},
+	"E_YnddState_StateEntry_Processor_Type": {
+		1: {Name: "drop"},
+		2: {Name: "rename"},
+		3: {Name: "convert"},
+		4: {Name: "map-value"},
+		5: {Name: "add-tag"},
+	},
+}
+
 var (
 	// ySchema is a byte slice contain a gzip compressed representation of the
 	// YANG schema from which the Go code was generated. When uncompressed the
@@ -337,35 +1087,94 @@ var (
 	// contents of a goyang yang.Entry struct, which defines the schema for the
 	// fields within the struct.
 	ySchema = []byte{
-		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x56, 0xcd, 0x6e, 0xf2, 0x30,
-		0x10, 0xbc, 0xe7, 0x29, 0x2c, 0x9f, 0xf9, 0x44, 0xa2, 0x2f, 0xfc, 0x34, 0x37, 0x5a, 0xa8, 0x2a,
-		0xd1, 0x3f, 0x95, 0x5e, 0x7a, 0xaa, 0xac, 0x78, 0x01, 0xab, 0xe0, 0x20, 0xc7, 0x69, 0x89, 0x2a,
-		0xde, 0xbd, 0x4a, 0x1c, 0x42, 0x12, 0x62, 0x93, 0xb4, 0x47, 0xb8, 0xc1, 0x7a, 0xec, 0x19, 0xef,
-		0xec, 0x66, 0xfd, 0x6d, 0x21, 0x84, 0x10, 0x7e, 0x24, 0x6b, 0xc0, 0x1e, 0xc2, 0x14, 0x3e, 0x99,
-		0x0f, 0xb8, 0xa3, 0xa2, 0x53, 0xc6, 0x29, 0xf6, 0x90, 0x93, 0xfd, 0xbd, 0x09, 0xf8, 0x9c, 0x2d,
-		0xb0, 0x87, 0xec, 0x2c, 0x30, 0x66, 0x02, 0x7b, 0x48, 0x1d, 0x91, 0x06, 0x42, 0x49, 0x24, 0x4c,
-		0xb8, 0x14, 0x71, 0x29, 0x5e, 0xa2, 0x28, 0x60, 0x3a, 0x65, 0x44, 0x99, 0x2e, 0x0f, 0x57, 0x69,
-		0xf3, 0x85, 0x67, 0x01, 0x73, 0xb6, 0x3d, 0x62, 0x2a, 0xb1, 0xc5, 0x9c, 0xd2, 0x7f, 0x29, 0x65,
-		0x85, 0x2d, 0x45, 0xcd, 0x82, 0x48, 0xf8, 0x50, 0x7b, 0x82, 0x52, 0x04, 0xf1, 0x57, 0x20, 0x12,
-		0x51, 0x78, 0xa3, 0xc8, 0x3a, 0xf5, 0xc0, 0x3b, 0x12, 0x8e, 0xc4, 0x22, 0x5a, 0x03, 0x97, 0xd8,
-		0x43, 0x52, 0x44, 0xa0, 0x01, 0x16, 0x50, 0x45, 0x6d, 0x47, 0xe0, 0x5d, 0x29, 0xb2, 0xab, 0xdc,
-		0xbc, 0x9a, 0xf8, 0x7c, 0x81, 0xab, 0x6b, 0x6b, 0xae, 0xb3, 0x4f, 0x4a, 0x8a, 0xd2, 0x08, 0xcc,
-		0x4c, 0xb0, 0x35, 0xcb, 0x3a, 0x33, 0x9a, 0x98, 0xd2, 0xce, 0x9c, 0xa6, 0x26, 0xb5, 0x36, 0xab,
-		0xb5, 0x69, 0xad, 0xcd, 0xab, 0x37, 0x51, 0x63, 0x66, 0x7e, 0xfa, 0x6b, 0xbc, 0x81, 0x66, 0x79,
-		0x0b, 0xa5, 0x60, 0x7c, 0x61, 0xca, 0xd9, 0xbe, 0x95, 0x86, 0x56, 0x33, 0x5d, 0x35, 0x9a, 0xf0,
-		0x86, 0xc8, 0xe5, 0xe9, 0x5a, 0x4a, 0x51, 0x97, 0x5a, 0x3a, 0xdf, 0x5a, 0xd2, 0x28, 0xb8, 0x67,
-		0xa1, 0x1c, 0x49, 0x29, 0xcc, 0x2a, 0x1e, 0x18, 0x9f, 0xac, 0x20, 0xc9, 0x43, 0xa8, 0xaf, 0x03,
-		0x85, 0x24, 0xdb, 0x02, 0xd2, 0x19, 0xba, 0x6e, 0x7f, 0xe0, 0xba, 0xf6, 0xe0, 0xff, 0xc0, 0xbe,
-		0xea, 0xf5, 0x9c, 0xbe, 0xd3, 0x33, 0x6c, 0x7e, 0x12, 0x14, 0x04, 0xd0, 0xeb, 0x64, 0x2c, 0xf1,
-		0x68, 0xb5, 0xfa, 0x4b, 0x57, 0x98, 0x6b, 0xf2, 0xd0, 0x17, 0xc6, 0x79, 0x71, 0xe9, 0x8c, 0xb3,
-		0xfc, 0xca, 0x1a, 0x87, 0xfa, 0x14, 0x62, 0xcd, 0x70, 0x36, 0x37, 0xd3, 0xe9, 0x26, 0xfa, 0x55,
-		0xf3, 0x98, 0x9b, 0xa6, 0x2a, 0x7e, 0xc4, 0x79, 0x20, 0x89, 0x64, 0x01, 0xaf, 0xd7, 0x18, 0xfa,
-		0x4b, 0x58, 0x93, 0x6c, 0xa4, 0xe0, 0xee, 0xc1, 0xd7, 0xae, 0xf6, 0x3d, 0x98, 0xbd, 0x28, 0x45,
-		0xe4, 0xcb, 0xec, 0x59, 0x83, 0xdf, 0x38, 0xa5, 0xb3, 0x04, 0xff, 0x3e, 0x3b, 0xec, 0xb2, 0xea,
-		0x53, 0xac, 0x7e, 0x65, 0x3a, 0x75, 0xfa, 0x30, 0x0b, 0x6f, 0xc9, 0x07, 0xbc, 0x04, 0xc1, 0x71,
-		0x71, 0x56, 0x35, 0xe3, 0xe2, 0x52, 0x49, 0xd6, 0x58, 0xbd, 0x9a, 0x15, 0xa1, 0xb5, 0xfb, 0x01,
-		0x00, 0x00, 0xff, 0xff, 0x01, 0x00, 0x00, 0xff, 0xff, 0x7a, 0xcb, 0xfb, 0x88, 0x54, 0x0b, 0x00,
-		0x00,
+		0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xec, 0x9c, 0xdd, 0x6f, 0xdb, 0x36,
+		0x10, 0xc0, 0xdf, 0xfd, 0x57, 0x10, 0x7c, 0x4a, 0x56, 0x6b, 0xb1, 0x53, 0x27, 0xa9, 0xfd, 0x32,
+		0x64, 0x4b, 0x8a, 0x01, 0x5d, 0xb7, 0xa0, 0x29, 0x06, 0x6c, 0xa9, 0x11, 0x30, 0xd2, 0xd9, 0x16,
+		0x2a, 0x51, 0x06, 0x45, 0x65, 0xf1, 0x16, 0xff, 0xef, 0x83, 0x2d, 0xcb, 0x96, 0x3f, 0x44, 0x1d,
+		0xa5, 0xa4, 0xcd, 0xc7, 0xe9, 0x29, 0x91, 0x8f, 0x5f, 0xa7, 0xdf, 0x9d, 0xee, 0x48, 0x8a, 0xff,
+		0x35, 0x18, 0x63, 0x8c, 0xff, 0x2e, 0x42, 0xe0, 0x3d, 0xc6, 0x3d, 0xb8, 0xf5, 0x5d, 0xe0, 0xcd,
+		0xf4, 0xee, 0x07, 0x5f, 0x7a, 0xbc, 0xc7, 0xda, 0x8b, 0x7f, 0x7f, 0x89, 0xe4, 0xc0, 0x1f, 0xf2,
+		0x1e, 0x6b, 0x2d, 0x6e, 0x9c, 0xf9, 0x8a, 0xf7, 0x58, 0x5a, 0x05, 0x63, 0x8c, 0xf1, 0x58, 0x0b,
+		0x0d, 0xe7, 0x52, 0xab, 0xc9, 0xda, 0xfd, 0xb5, 0x26, 0x72, 0x32, 0xcd, 0x75, 0x89, 0xf5, 0xe6,
+		0x96, 0xb7, 0x37, 0x9b, 0x5d, 0xfe, 0x70, 0xa1, 0x60, 0xe0, 0xdf, 0x6d, 0xb5, 0xb4, 0xd6, 0xda,
+		0x44, 0x7a, 0x9e, 0x33, 0x6f, 0x92, 0x37, 0xb7, 0xa5, 0x2e, 0xa3, 0x44, 0xb9, 0xb0, 0xb3, 0x86,
+		0xb4, 0x47, 0x30, 0xf9, 0x27, 0x52, 0xb3, 0x4e, 0xf1, 0x71, 0xda, 0x58, 0x73, 0xb7, 0xe0, 0xaf,
+		0x22, 0x3e, 0x55, 0xc3, 0x24, 0x04, 0xa9, 0x79, 0x8f, 0x69, 0x95, 0x40, 0x81, 0x60, 0x4e, 0x2a,
+		0xdf, 0xb7, 0x2d, 0xe1, 0xe9, 0xda, 0x9d, 0xe9, 0xc6, 0xc8, 0x37, 0x15, 0xbf, 0xfc, 0xc1, 0x1d,
+		0x09, 0x39, 0x84, 0xd8, 0x89, 0x64, 0x30, 0x29, 0x1e, 0x56, 0xa6, 0x9c, 0x35, 0xe9, 0x82, 0x0e,
+		0x9f, 0xc1, 0x40, 0x24, 0xc1, 0xac, 0xbf, 0x57, 0x3b, 0x05, 0x18, 0x63, 0x8c, 0x0f, 0x44, 0x10,
+		0xef, 0x18, 0x04, 0x63, 0x8c, 0xf5, 0x0b, 0xea, 0x5d, 0x3c, 0xec, 0x56, 0xc1, 0xcf, 0x45, 0x0f,
+		0x1d, 0xf3, 0xf0, 0xed, 0x20, 0xc0, 0xc2, 0x60, 0x0d, 0x85, 0x35, 0x1c, 0xd6, 0x90, 0xec, 0x86,
+		0xa5, 0x00, 0x9a, 0xec, 0xe2, 0x9f, 0x27, 0x63, 0xc0, 0xe9, 0xed, 0x26, 0x8a, 0x02, 0x10, 0xd2,
+		0xa4, 0xb4, 0xcc, 0x66, 0xdb, 0x0d, 0x5c, 0xc7, 0x76, 0x74, 0x8a, 0x7b, 0x10, 0x80, 0x06, 0x47,
+		0x0c, 0x34, 0x28, 0x47, 0x41, 0x3c, 0x91, 0x6e, 0x39, 0xbb, 0xbb, 0x0a, 0x11, 0xc2, 0x84, 0xf0,
+		0xf7, 0x41, 0x18, 0xa4, 0x1b, 0x79, 0xbe, 0x1c, 0x96, 0x73, 0xbb, 0x94, 0xac, 0x03, 0xab, 0x88,
+		0x5d, 0xdf, 0x27, 0x58, 0x5f, 0x1a, 0xac, 0x20, 0x93, 0x10, 0x94, 0xd0, 0x7e, 0x84, 0x02, 0xb6,
+		0x63, 0x90, 0x39, 0x97, 0x49, 0x38, 0x6b, 0x74, 0x5a, 0x03, 0xea, 0x11, 0x08, 0xa5, 0x6f, 0x40,
+		0x68, 0xc7, 0x97, 0x1a, 0xd4, 0xad, 0x08, 0xca, 0xf1, 0xde, 0x51, 0x86, 0x80, 0x7c, 0xb6, 0x40,
+		0xc6, 0x5a, 0x15, 0xbb, 0xaa, 0x35, 0x16, 0xdf, 0x19, 0x64, 0x2e, 0x84, 0xd6, 0xa0, 0xa4, 0xd1,
+		0xa5, 0x31, 0xc6, 0x18, 0xdf, 0xbb, 0x6a, 0x39, 0xdd, 0xfe, 0x9b, 0xbd, 0x2f, 0x5f, 0x7e, 0x4c,
+		0xff, 0xda, 0xff, 0x69, 0x4f, 0xc6, 0xf7, 0x49, 0x7c, 0x1f, 0xc6, 0xf7, 0xf1, 0x7d, 0x78, 0x3f,
+		0xda, 0xdf, 0x7f, 0x53, 0xac, 0x85, 0x7e, 0x0d, 0xd6, 0xc3, 0xc8, 0x83, 0x72, 0xba, 0xe7, 0x52,
+		0x75, 0x1c, 0x77, 0x24, 0x9d, 0x34, 0xe8, 0x26, 0xe7, 0x4d, 0xce, 0xfb, 0x51, 0x9d, 0xb7, 0x14,
+		0x61, 0x71, 0xc7, 0x97, 0x9d, 0x9e, 0x4b, 0x11, 0x74, 0xaf, 0xc1, 0x41, 0xd7, 0x60, 0x69, 0x2c,
+		0xf4, 0xa8, 0x9c, 0xa5, 0xb9, 0x14, 0xb1, 0xf4, 0x7a, 0x59, 0x2a, 0xe8, 0xc1, 0x6f, 0x7e, 0xac,
+		0x4f, 0xb5, 0x56, 0xe6, 0x5e, 0x7c, 0xf4, 0xe5, 0x79, 0x00, 0x33, 0x3d, 0xc4, 0xc5, 0x1c, 0xa4,
+		0x92, 0xe2, 0x2e, 0x27, 0xd9, 0x7e, 0xd7, 0xe9, 0x1c, 0x9f, 0x74, 0x3a, 0xad, 0x93, 0xb7, 0x27,
+		0xad, 0xee, 0xd1, 0x51, 0xfb, 0xb8, 0x7d, 0x64, 0x28, 0xfc, 0x87, 0xf2, 0x40, 0x81, 0xf7, 0xf3,
+		0x84, 0xf7, 0x98, 0x4c, 0x82, 0xa0, 0x8e, 0x55, 0x98, 0x99, 0x5c, 0xd9, 0x85, 0x71, 0xe6, 0x90,
+		0x2c, 0x83, 0xbc, 0x6c, 0xc6, 0x53, 0xe4, 0x42, 0x1c, 0x47, 0x0a, 0x83, 0x54, 0x26, 0x6a, 0xa6,
+		0xaa, 0x4d, 0x54, 0x7d, 0x6b, 0xaa, 0x8a, 0xa6, 0xe6, 0xb3, 0x8b, 0xbb, 0x91, 0xbc, 0x05, 0xa5,
+		0x1d, 0x1d, 0x95, 0x2b, 0x63, 0x39, 0x51, 0xbf, 0x2a, 0x53, 0x32, 0x38, 0xb3, 0x3b, 0x41, 0x03,
+		0x60, 0x03, 0x42, 0x35, 0x20, 0x6c, 0xc1, 0xa8, 0x0c, 0x48, 0x65, 0x50, 0x2a, 0x03, 0x63, 0x06,
+		0xa7, 0x04, 0x20, 0xbc, 0x7b, 0xaa, 0x98, 0x81, 0xd8, 0x64, 0x22, 0xc8, 0x8c, 0xa4, 0x7c, 0xc0,
+		0x86, 0xc1, 0x72, 0x5f, 0x7a, 0x70, 0x87, 0xb7, 0x85, 0x54, 0x9c, 0xcc, 0x80, 0xcc, 0xa0, 0x40,
+		0xef, 0x89, 0x2f, 0xf5, 0xdb, 0x43, 0x0b, 0x0b, 0x38, 0x41, 0x88, 0x7e, 0x9a, 0x4f, 0xda, 0x94,
+		0xcd, 0x60, 0x65, 0x17, 0xee, 0x19, 0xb2, 0x45, 0xdc, 0xcb, 0x7b, 0x16, 0x05, 0x18, 0x63, 0x8c,
+		0xff, 0x29, 0x82, 0x04, 0xca, 0xb1, 0xdd, 0xbc, 0xf8, 0x7b, 0x25, 0xdc, 0x99, 0x7b, 0x38, 0xf3,
+		0x87, 0x7e, 0x59, 0x9c, 0xbd, 0x5b, 0xc7, 0x30, 0x14, 0xda, 0xbf, 0x9d, 0xb5, 0x3d, 0x5f, 0x30,
+		0x43, 0x97, 0x9e, 0x36, 0x2d, 0x54, 0x22, 0xee, 0xaa, 0xab, 0xa4, 0x73, 0xd8, 0xed, 0x74, 0x8f,
+		0x4f, 0x0e, 0xbb, 0x47, 0xcf, 0x47, 0x37, 0x8d, 0x87, 0x91, 0xea, 0x3f, 0x82, 0x73, 0x9e, 0x25,
+		0xf5, 0x4e, 0x28, 0xb4, 0x3b, 0xc2, 0x7b, 0xe8, 0x5c, 0x19, 0x72, 0xd3, 0xe4, 0xa6, 0xab, 0x26,
+		0x55, 0xc8, 0xe4, 0xaa, 0x1e, 0xdf, 0x0a, 0xc6, 0x81, 0x70, 0x61, 0xa1, 0x30, 0x24, 0xe0, 0xf9,
+		0x42, 0x44, 0x38, 0x11, 0xfe, 0xa4, 0x09, 0xd7, 0x62, 0x88, 0x27, 0x7b, 0x26, 0x8c, 0x23, 0xba,
+		0x4d, 0x44, 0x3f, 0x77, 0xa2, 0xcb, 0xa6, 0x2a, 0xb2, 0xcb, 0xbc, 0x96, 0x54, 0xf8, 0x90, 0x0c,
+		0x6b, 0x4b, 0x15, 0xdd, 0xa4, 0x35, 0x5c, 0x55, 0x20, 0xab, 0x07, 0x5b, 0x55, 0xe8, 0x6a, 0xc3,
+		0x57, 0x1b, 0xc2, 0xda, 0x30, 0xe2, 0xa0, 0x44, 0xc2, 0x69, 0xef, 0x76, 0xab, 0xbb, 0x5f, 0x4b,
+		0x37, 0x8c, 0x1f, 0x27, 0x62, 0x8c, 0xfc, 0x76, 0x91, 0xb7, 0x58, 0x1a, 0x57, 0x5a, 0x8c, 0xac,
+		0x8b, 0xac, 0xeb, 0xf5, 0x5a, 0x57, 0xad, 0x97, 0xdf, 0x07, 0x98, 0x20, 0x5f, 0x52, 0xb8, 0x05,
+		0xcc, 0xfc, 0x84, 0x0e, 0x6e, 0x21, 0xf3, 0x41, 0x16, 0x34, 0xed, 0x16, 0x36, 0xb1, 0xca, 0x39,
+		0x95, 0x32, 0xd2, 0xe9, 0x44, 0x32, 0x6a, 0xcc, 0xb1, 0x3b, 0x82, 0x50, 0x2c, 0xb6, 0x09, 0xf0,
+		0x83, 0x15, 0xd7, 0x07, 0xab, 0xaf, 0x3d, 0x0e, 0x96, 0x8b, 0x56, 0x07, 0xe5, 0x51, 0x66, 0x5a,
+		0xab, 0x56, 0x89, 0xab, 0x17, 0xc1, 0x07, 0xff, 0x4b, 0x7a, 0xde, 0xe5, 0xac, 0xb6, 0xeb, 0xcb,
+		0x65, 0x9d, 0xd7, 0x17, 0x59, 0x9d, 0xd7, 0x9f, 0xc5, 0x90, 0x3f, 0x46, 0xf4, 0x8c, 0x31, 0x90,
+		0x55, 0xf8, 0x3c, 0x93, 0xa6, 0x8c, 0x90, 0x32, 0xc2, 0x97, 0xb5, 0x42, 0x33, 0x0f, 0x36, 0x9c,
+		0x50, 0x8c, 0xf1, 0x96, 0xb0, 0x2a, 0x42, 0xe9, 0x24, 0xa5, 0x93, 0x6b, 0x17, 0x1f, 0xa8, 0x28,
+		0xb4, 0x8f, 0x78, 0xe7, 0xa5, 0x28, 0xe0, 0xa5, 0x80, 0xb7, 0x0a, 0x9c, 0xf6, 0x3e, 0xfb, 0x29,
+		0x06, 0xbc, 0xe5, 0x6d, 0x73, 0xc4, 0xae, 0x92, 0xad, 0x91, 0x95, 0xee, 0x2a, 0x21, 0xbb, 0x22,
+		0xbb, 0x7a, 0xc9, 0x76, 0xf5, 0x10, 0x89, 0x24, 0xe2, 0xf5, 0x44, 0x89, 0xe4, 0x43, 0x27, 0x92,
+		0xd8, 0x28, 0x93, 0x59, 0xa7, 0x93, 0xf3, 0xed, 0x04, 0x1f, 0xc5, 0x98, 0x3f, 0x62, 0x38, 0x6d,
+		0xb5, 0xa8, 0x9e, 0x2f, 0x44, 0x19, 0x26, 0x65, 0x98, 0x75, 0xbd, 0x69, 0xfd, 0x35, 0x47, 0xab,
+		0x6d, 0xb1, 0x0b, 0x2f, 0x69, 0xda, 0xbc, 0xf7, 0x1c, 0x3f, 0x14, 0x68, 0x36, 0xea, 0xb8, 0x3d,
+		0x4b, 0x77, 0x67, 0x78, 0xaa, 0x56, 0xce, 0x8d, 0xd7, 0xdb, 0xa4, 0x1e, 0x82, 0x1e, 0x41, 0x12,
+		0xa3, 0x76, 0xa9, 0x67, 0xb2, 0xb4, 0x4d, 0xfd, 0xb9, 0x6d, 0x53, 0x07, 0x29, 0x6e, 0x02, 0xf0,
+		0xf0, 0x2f, 0xa8, 0xac, 0x40, 0xd9, 0x2c, 0x01, 0xe2, 0x33, 0xd9, 0xec, 0xe2, 0x33, 0x35, 0x99,
+		0x7d, 0x6c, 0x9f, 0xde, 0x85, 0xf4, 0x2e, 0xac, 0x7c, 0xf6, 0x07, 0xf2, 0x0c, 0x10, 0xc4, 0xcb,
+		0xb0, 0xb8, 0x0d, 0x1e, 0x88, 0x1b, 0x08, 0x9c, 0xaf, 0x30, 0xc1, 0xdb, 0xd2, 0xaa, 0x08, 0xe1,
+		0x4d, 0x78, 0x7f, 0xab, 0x50, 0xef, 0xf5, 0x65, 0xaf, 0x95, 0xec, 0x39, 0x04, 0xad, 0x7c, 0xd7,
+		0x41, 0x6d, 0x8a, 0x5a, 0x3e, 0xac, 0x7c, 0x21, 0xb2, 0x69, 0xb2, 0xe9, 0x87, 0xb3, 0x69, 0x84,
+		0x2c, 0xf6, 0x00, 0x96, 0xec, 0xe2, 0x57, 0xc2, 0xf9, 0xf7, 0xd4, 0xf9, 0xfb, 0xba, 0xd7, 0x5f,
+		0xfc, 0xd5, 0x72, 0xba, 0xd7, 0xbd, 0xfe, 0x0f, 0xfc, 0xf1, 0xbe, 0x41, 0xf8, 0xae, 0xf9, 0x95,
+		0x39, 0x4d, 0x61, 0xf8, 0x04, 0x2b, 0xab, 0xa8, 0x46, 0x86, 0x15, 0x8b, 0x70, 0x1c, 0x80, 0xc5,
+		0x91, 0x4b, 0x9b, 0x05, 0xe8, 0x43, 0x73, 0x3a, 0x6f, 0xe9, 0xc9, 0x9f, 0xb7, 0x14, 0x6b, 0x11,
+		0x2c, 0x4e, 0x6f, 0x44, 0x10, 0x9e, 0x13, 0x26, 0xba, 0x89, 0xee, 0xa7, 0x4f, 0x77, 0x32, 0x1e,
+		0x2b, 0x88, 0x63, 0x47, 0x81, 0x97, 0x48, 0x4f, 0x18, 0x3e, 0x2e, 0x5a, 0x69, 0x67, 0xbb, 0x0c,
+		0x9d, 0x67, 0x4a, 0x36, 0x64, 0x3d, 0xa7, 0x61, 0x7f, 0x9e, 0xa9, 0xf1, 0xa8, 0xe9, 0xe2, 0x7d,
+		0xb2, 0xe6, 0x84, 0xb0, 0x3c, 0x01, 0xac, 0x94, 0xf0, 0x99, 0x13, 0xbc, 0xcd, 0xce, 0x97, 0xc4,
+		0x89, 0xa8, 0xf8, 0x90, 0x37, 0x1b, 0x15, 0x82, 0x41, 0xde, 0xd8, 0xad, 0xe2, 0x69, 0x23, 0xd7,
+		0xcf, 0xa2, 0xfe, 0x71, 0x3f, 0x7e, 0x2f, 0xbe, 0xc2, 0xa7, 0x28, 0xda, 0xa6, 0x73, 0xb3, 0xcf,
+		0xbc, 0xd9, 0x28, 0xe8, 0xd6, 0x59, 0x7a, 0x96, 0x7b, 0xda, 0x60, 0x63, 0xfa, 0x3f, 0x00, 0x00,
+		0x00, 0xff, 0xff, 0x03, 0x00, 0x7f, 0x62, 0x0b, 0x46, 0xea, 0x5d, 0x00, 0x00,
 	}
 )
 
@@ -373,5 +1182,18 @@ var (
 // correspond with the leaf. The type is represented as a reflect.Type. The naming
 // of the map ensures that there are no clashes with valid YANG identifiers.
 func initΛEnumTypes() {
-	ΛEnumTypes = map[string][]reflect.Type{}
+	ΛEnumTypes = map[string][]reflect.Type{
+		"/stateEntry/encoding": []reflect.Type{
+			reflect.TypeOf((E_YnddState_StateEntry_Encoding)(0)),
+		},
+		"/stateEntry/mode": []reflect.Type{
+			reflect.TypeOf((E_YnddState_StateEntry_Mode)(0)),
+		},
+		"/stateEntry/processor/convert-to": []reflect.Type{
+			reflect.TypeOf((E_YnddState_StateEntry_Processor_ConvertTo)(0)),
+		},
+		"/stateEntry/processor/type": []reflect.Type{
+			reflect.TypeOf((E_YnddState_StateEntry_Processor_Type)(0)),
+		},
+	}
 }